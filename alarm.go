@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+	"strings"
+	"time"
+)
+
+// nextOccurrence returns the next instant after now when the wall clock in
+// loc reads wall (HH:MM or HH:MM:SS): today if still ahead, else tomorrow
+func nextOccurrence(wall string, now time.Time, loc *time.Location) (time.Time, error) {
+	if !clockTime.MatchString(wall) {
+		return time.Time{}, fmt.Errorf("invalid clock time %q (expected HH:MM or HH:MM:SS)", wall)
+	}
+	layout := "15:04"
+	if strings.Count(wall, ":") == 2 {
+		layout = "15:04:05"
+	}
+	c, err := time.Parse(layout, wall)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid clock time %q", wall)
+	}
+
+	local := now.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), c.Hour(), c.Minute(), c.Second(), 0, loc)
+	if !next.After(now) {
+		next = time.Date(local.Year(), local.Month(), local.Day()+1, c.Hour(), c.Minute(), c.Second(), 0, loc)
+	}
+	return next, nil
+}
+
+// runAlarmCommand waits for the next occurrence of a wall-clock time, then
+// notifies and runs the optional command given after "--"
+func runAlarmCommand(args []string) error {
+	loc := time.Local
+	fs := newFlagSet("alarm")
+	fs.Var(locationValue{&loc}, "tz", "time zone of the clock time")
+	// Only what follows "--" is ever run, never a stray word
+	var command []string
+	if i := slices.Index(args, "--"); i >= 0 {
+		args, command = args[:i], args[i+1:]
+	}
+	positional, err := parseArgs("alarm", fs, args)
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(positional) == 0:
+		return fmt.Errorf("alarm requires a clock time (e.g. timeago alarm 07:30)")
+	case len(positional) > 1:
+		return fmt.Errorf("alarm takes one clock time, got %q (give a command after --)", strings.Join(positional, " "))
+	}
+	wall := positional[0]
+	isTTY := isTTY()
+
+	at, err := nextOccurrence(wall, clock.Now(), loc)
+	if err != nil {
+		return err
+	}
+
+	if isTTY {
+		fmt.Printf("Alarm: %s %s (%s)\n", at.In(loc).Format("2006-01-02 15:04:05"), loc, timeAgo(at.UnixMilli(), 2))
+		if loc != time.Local {
+			fmt.Printf("Local: %s\n", formatDateTime(at.Local(), false))
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := waitUntil(ctx, at, "Alarm in", isTTY); err != nil {
+		return err
+	}
+
+	notify("timeago alarm", fmt.Sprintf("It is %s", wall), isTTY)
+	if isTTY {
+		fmt.Printf("Alarm: %s reached\n", wall)
+	} else {
+		fmt.Println(emitEpoch(at.UnixMilli()))
+	}
+
+	if len(command) > 0 {
+		cmd := exec.Command(command[0], command[1:]...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// allDescription details the all command for its usage
+const allDescription = `Prints everything about one instant (now by default): the epoch in four
+units, RFC 3339, RFC 1123, the ISO week and ordinal dates, the Julian day,
+the weekday and quarter in the local zone, the relative time and the time
+in a set of zones (--tz, repeatable, else the config "zones" or a set of
+major cities). --astro adds the moon phase and, at --lat/--lon, sunrise,
+sunset and day length. Piped output is "field<TAB>value" lines.`
+
+// julianDay returns the Julian day number of t, counted from noon UTC on
+// November 24, 4714 BC (proleptic Gregorian)
+func julianDay(t time.Time) float64 {
+	return float64(t.UnixMilli())/float64(24*time.Hour/time.Millisecond) + 2440587.5
+}
+
+// isoWeekDate renders t as an ISO 8601 week date, e.g. "2023-W46-2"
+func isoWeekDate(t time.Time) string {
+	year, week := t.ISOWeek()
+	weekday := int(t.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	return fmt.Sprintf("%04d-W%02d-%d", year, week, weekday)
+}
+
+// runAllCommand prints every representation of a timestamp
+func runAllCommand(args []string) error {
+	fs := newFlagSet("all")
+	out := addOutputFlags(fs, 2)
+	var zones zonesValue
+	fs.Var(&zones, "tz", "show the time in this IANA zone (repeatable)")
+	astro := addAstroFlags(fs)
+	positional, err := parseArgs("all", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if err := astro.check(); err != nil {
+		return err
+	}
+
+	t := clock.Now()
+	if len(positional) > 0 {
+		input := strings.Join(positional, " ")
+		epochMs, err := parseEpoch(input)
+		if err != nil {
+			return fmt.Errorf("Invalid timestamp %q (expected epoch milliseconds, ISO 8601 or a date phrase)", input)
+		}
+		t = time.UnixMilli(epochMs)
+	}
+	epochMs := t.UnixMilli()
+
+	if len(zones) == 0 {
+		names, err := loadZones()
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			// UTC has its own line already
+			if name == "UTC" {
+				continue
+			}
+			if err := zones.Set(strings.TrimSpace(name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	local := t.Local()
+	ns := new(big.Int).Mul(big.NewInt(epochMs), big.NewInt(int64(time.Millisecond)))
+	us := new(big.Int).Mul(big.NewInt(epochMs), big.NewInt(int64(time.Millisecond/time.Microsecond)))
+	fields := [][2]string{
+		{"Epoch (s)", fmt.Sprint(t.Unix())},
+		{"Epoch (ms)", fmt.Sprint(epochMs)},
+		{"Epoch (us)", us.String()},
+		{"Epoch (ns)", ns.String()},
+		{"UTC", formatDateTime(t, true)},
+		{"Local", formatInZone(t, time.Local)},
+		{"RFC 3339", t.UTC().Format(time.RFC3339Nano)},
+		{"RFC 3339 local", local.Format(time.RFC3339Nano)},
+		{"RFC 1123", t.UTC().Format(http.TimeFormat)},
+		{"ISO week date", isoWeekDate(local)},
+		{"Ordinal date", fmt.Sprintf("%04d-%03d", local.Year(), local.YearDay())},
+		{"Julian day", fmt.Sprintf("%.5f", julianDay(t))},
+		{"Weekday", local.Weekday().String()},
+		{"Quarter", fmt.Sprintf("Q%d %d", (int(local.Month())+2)/3, local.Year())},
+		{"Relative", timeAgo(epochMs, out.precision)},
+	}
+	if astro.on {
+		fields = append(fields, astro.fields(t, out.precision)...)
+	}
+	for _, loc := range zones {
+		fields = append(fields, [2]string{loc.String(), formatInZone(t, loc)})
+	}
+
+	if !isTTY() {
+		for _, f := range fields {
+			fmt.Printf("%s\t%s\n", f[0], f[1])
+		}
+		return nil
+	}
+	width := 0
+	for _, f := range fields {
+		width = max(width, len(f[0]))
+	}
+	for _, f := range fields {
+		fmt.Printf("%-*s  %s\n", width+1, f[0]+":", f[1])
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// archiveEntry is one file of an archive with its embedded mtime
+type archiveEntry struct {
+	name    string
+	modTime time.Time
+}
+
+// readTarEntries lists a tar stream, transparently decompressing gzip/bzip2
+func readTarEntries(path string) ([]archiveEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	switch {
+	case strings.HasSuffix(path, ".gz"), strings.HasSuffix(path, ".tgz"):
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	case strings.HasSuffix(path, ".bz2"), strings.HasSuffix(path, ".tbz2"):
+		r = bzip2.NewReader(f)
+	}
+
+	var entries []archiveEntry
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, archiveEntry{name: hdr.Name, modTime: hdr.ModTime})
+	}
+}
+
+// readZipEntries lists a zip archive
+func readZipEntries(path string) ([]archiveEntry, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	entries := make([]archiveEntry, 0, len(zr.File))
+	for _, f := range zr.File {
+		entries = append(entries, archiveEntry{name: f.Name, modTime: f.Modified})
+	}
+	return entries, nil
+}
+
+// zipEpoch is the earliest MS-DOS timestamp, used by zip tools as "no date"
+var zipEpoch = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// suspiciousTimestamp flags zero-like and future mtimes
+func suspiciousTimestamp(t time.Time, now time.Time) string {
+	switch {
+	case t.IsZero() || t.Unix() <= 0:
+		return "zero"
+	case t.Equal(zipEpoch):
+		return "zero"
+	case t.After(now):
+		return "future"
+	}
+	return ""
+}
+
+// runArchiveCommand lists archive entries with humanized mtimes. Piped output is
+// tab separated: epoch ms, flag and name.
+func runArchiveCommand(args []string) error {
+	fs := newFlagSet("archive")
+	out := addOutputFlags(fs, 1)
+	positional, err := parseArgs("archive", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return fmt.Errorf("archive requires a file (.tar, .tar.gz, .tgz, .tar.bz2 or .zip)")
+	}
+	path := positional[0]
+	precision := out.precision
+	isTTY := isTTY()
+
+	var entries []archiveEntry
+	if strings.HasSuffix(strings.ToLower(path), ".zip") || strings.HasSuffix(strings.ToLower(path), ".jar") {
+		entries, err = readZipEntries(path)
+	} else {
+		entries, err = readTarEntries(path)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot read %s: %s", path, err)
+	}
+
+	now := clock.Now()
+	suspicious := 0
+	for _, e := range entries {
+		flag := suspiciousTimestamp(e.modTime, now)
+		if flag != "" {
+			suspicious++
+		}
+
+		if !isTTY {
+			fmt.Printf("%d\t%s\t%s\n", emitEpoch(e.modTime.UnixMilli()), flag, e.name)
+			continue
+		}
+		marker := ""
+		if flag != "" {
+			marker = " [" + flag + "]"
+		}
+		fmt.Printf("%s  %-24s %s%s\n", formatDateTime(e.modTime.Local(), false), timeAgo(e.modTime.UnixMilli(), precision), e.name, marker)
+	}
+
+	if isTTY {
+		fmt.Printf("%d entries, %d with suspicious timestamps\n", len(entries), suspicious)
+	}
+	return nil
+}
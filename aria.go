@@ -0,0 +1,20 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"time"
+)
+
+// ariaFragment renders an accessible <time> element: screen readers get the
+// full absolute time through aria-label while the short relative string is
+// displayed
+func ariaFragment(epochMs int64, precision int) string {
+	t := time.UnixMilli(epochMs).UTC()
+	label := t.Format("Monday, January 2, 2006 at 15:04:05 MST")
+	return fmt.Sprintf(`<time datetime="%s" aria-label="%s" title="%s">%s</time>`,
+		t.Format(time.RFC3339),
+		html.EscapeString(label),
+		html.EscapeString(label),
+		html.EscapeString(timeAgo(epochMs, precision)))
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"time"
+)
+
+// synodicMonth is the mean length of a lunar cycle in days
+const synodicMonth = 29.530588853
+
+// referenceNewMoon is the new moon of 2000-01-06 18:14 UTC
+var referenceNewMoon = time.Date(2000, 1, 6, 18, 14, 0, 0, time.UTC)
+
+// moonPhaseNames are the eight phases, from the new moon
+var moonPhaseNames = []string{
+	"New moon", "Waxing crescent", "First quarter", "Waxing gibbous",
+	"Full moon", "Waning gibbous", "Last quarter", "Waning crescent",
+}
+
+// moonAge returns the days since the last new moon at t, from the mean
+// cycle (within about a day of the true phase)
+func moonAge(t time.Time) float64 {
+	days := t.Sub(referenceNewMoon).Hours() / 24
+	return math.Mod(math.Mod(days, synodicMonth)+synodicMonth, synodicMonth)
+}
+
+// moonPhase names the phase at t and gives the illuminated fraction
+func moonPhase(t time.Time) (string, float64) {
+	age := moonAge(t)
+	illuminated := (1 - math.Cos(2*math.Pi*age/synodicMonth)) / 2
+	index := int(math.Floor(age/synodicMonth*8+0.5)) % 8
+	return moonPhaseNames[index], illuminated
+}
+
+// nextMoon returns the first instant after t at the given age in days
+func nextMoon(t time.Time, age float64) time.Time {
+	wait := math.Mod(age-moonAge(t)+synodicMonth, synodicMonth)
+	return t.Add(time.Duration(wait * 24 * float64(time.Hour)))
+}
+
+// sunTimes returns sunrise and sunset on the calendar day of day at lat and
+// lon (degrees, east positive) with the sunrise equation; polar is "polar
+// night" or "midnight sun" when the sun does not rise or set that day
+func sunTimes(day time.Time, lat, lon float64) (rise, set time.Time, polar string) {
+	rad := math.Pi / 180
+	noon := time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, time.UTC)
+	n := math.Round(julianDay(noon) - 2451545.0 + 0.0008)
+	mean := n - lon/360
+	m := math.Mod(357.5291+0.98560028*mean, 360)
+	c := 1.9148*math.Sin(m*rad) + 0.02*math.Sin(2*m*rad) + 0.0003*math.Sin(3*m*rad)
+	ecliptic := math.Mod(m+c+180+102.9372, 360)
+	transit := 2451545.0 + mean + 0.0053*math.Sin(m*rad) - 0.0069*math.Sin(2*ecliptic*rad)
+	declination := math.Asin(math.Sin(ecliptic*rad) * math.Sin(23.4397*rad))
+	// -0.833 degrees accounts for refraction and the solar disc
+	cosHour := (math.Sin(-0.833*rad) - math.Sin(lat*rad)*math.Sin(declination)) / (math.Cos(lat*rad) * math.Cos(declination))
+	switch {
+	case cosHour > 1:
+		return time.Time{}, time.Time{}, "polar night"
+	case cosHour < -1:
+		return time.Time{}, time.Time{}, "midnight sun"
+	}
+	hour := math.Acos(cosHour) / rad / 360
+	fromJulian := func(j float64) time.Time {
+		return time.UnixMilli(int64(math.Round((j - 2440587.5) * 86400000)))
+	}
+	return fromJulian(transit - hour), fromJulian(transit + hour), ""
+}
+
+// dayLength returns the time between sunrise and sunset, 0 or 24 hours on
+// polar days
+func dayLength(day time.Time, lat, lon float64) time.Duration {
+	rise, set, polar := sunTimes(day, lat, lon)
+	switch polar {
+	case "polar night":
+		return 0
+	case "midnight sun":
+		return 24 * time.Hour
+	}
+	return set.Sub(rise)
+}
+
+// astroFlags are the --astro, --lat and --lon flags
+type astroFlags struct {
+	on       bool
+	lat, lon float64
+	located  bool
+}
+
+// addAstroFlags registers --astro, --lat and --lon on fs
+func addAstroFlags(fs *flag.FlagSet) *astroFlags {
+	a := &astroFlags{}
+	fs.BoolVar(&a.on, "astro", false, "also show the moon phase and, with --lat/--lon, sunrise, sunset and the day length trend")
+	fs.Float64Var(&a.lat, "lat", math.NaN(), "latitude for --astro in degrees, north positive")
+	fs.Float64Var(&a.lon, "lon", math.NaN(), "longitude for --astro in degrees, east positive")
+	return a
+}
+
+// check validates the coordinates once the flags are parsed
+func (a *astroFlags) check() error {
+	latSet, lonSet := !math.IsNaN(a.lat), !math.IsNaN(a.lon)
+	switch {
+	case (latSet || lonSet) && !a.on:
+		return errors.New("--lat and --lon require --astro")
+	case latSet != lonSet:
+		return errors.New("--lat and --lon must be given together")
+	case latSet && (a.lat < -90 || a.lat > 90 || a.lon < -180 || a.lon > 180):
+		return errors.New("--lat must be within -90..90 and --lon within -180..180")
+	}
+	a.located = latSet
+	return nil
+}
+
+// fields returns the astronomical lines for t, in the local zone; the next
+// moons are relative to t itself
+func (a *astroFlags) fields(t time.Time, precision int) [][2]string {
+	phase, illuminated := moonPhase(t)
+	full, newMoon := nextMoon(t, synodicMonth/2), nextMoon(t, 0)
+	fields := [][2]string{
+		{"Moon", fmt.Sprintf("%s, %.0f%% illuminated, %.1f days old", phase, illuminated*100, moonAge(t))},
+		{"Next full moon", fmt.Sprintf("%s (%s)", formatDateTime(full.Local(), false), timeAgoAt(full.UnixMilli(), 2, t))},
+		{"Next new moon", fmt.Sprintf("%s (%s)", formatDateTime(newMoon.Local(), false), timeAgoAt(newMoon.UnixMilli(), 2, t))},
+	}
+	if !a.located {
+		return fields
+	}
+
+	day := t.Local()
+	rise, set, polar := sunTimes(day, a.lat, a.lon)
+	if polar != "" {
+		fields = append(fields, [2]string{"Sun", polar})
+	} else {
+		fields = append(fields,
+			[2]string{"Sunrise", formatDateTime(rise.Local(), false)},
+			[2]string{"Sunset", formatDateTime(set.Local(), false)})
+	}
+
+	// Day lengths read best to the minute
+	f := newFormatter(max(precision, 2))
+	length := dayLength(day, a.lat, a.lon)
+	change := length - dayLength(day.AddDate(0, 0, -1), a.lat, a.lon)
+	trend := "the same as the day before"
+	switch {
+	case change.Abs() < time.Second:
+	case change > 0:
+		trend = f.Duration(change) + " longer than the day before"
+	default:
+		trend = f.Duration(change) + " shorter than the day before"
+	}
+	fields = append(fields, [2]string{"Day length", fmt.Sprintf("%s, %s", f.Duration(length.Round(time.Second)), trend)})
+	return fields
+}
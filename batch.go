@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// convertLine formats one converted timestamp of a batch
+type convertLine func(input string, epochMs int64) (string, error)
+
+// runLines applies convert to every line read from r. Output stays
+// line-aligned with the input: a line that cannot be converted, or is longer
+// than limit bytes, is reported on stderr and left blank, and the batch
+// fails once the input is drained. Memory stays bounded by limit whatever
+// the size of the input, and output is flushed whenever the input stalls.
+func runLines(r io.Reader, w io.Writer, limit int, convert func(input string) (string, error)) error {
+	reader := bufio.NewReaderSize(r, 64*1024)
+	out := bufio.NewWriter(w)
+
+	failed := 0
+	fail := func(lineNo int, err error) {
+		failed++
+		// Flushed first so the report follows the lines before it
+		out.WriteString("\n")
+		out.Flush()
+		fmt.Fprintf(os.Stderr, "line %d: %s\n", lineNo, err)
+	}
+
+	var line []byte
+	for lineNo := 1; ; {
+		chunk, err := reader.ReadSlice('\n')
+		line = append(line, chunk...)
+
+		switch {
+		case err == bufio.ErrBufferFull && len(line) <= limit:
+			continue
+		case err == bufio.ErrBufferFull:
+			// Oversized line: skip the rest without buffering it
+			size := len(line)
+			for err == bufio.ErrBufferFull {
+				chunk, err = reader.ReadSlice('\n')
+				size += len(chunk)
+			}
+			fail(lineNo, fmt.Errorf("longer than %d bytes (%d), skipped", limit, size))
+			lineNo++
+		case len(bytes.TrimRight(line, "\r\n")) > limit:
+			fail(lineNo, fmt.Errorf("longer than %d bytes (%d), skipped", limit, len(bytes.TrimRight(line, "\r\n"))))
+			lineNo++
+		case len(line) > 0:
+			if input := strings.TrimSpace(string(line)); input == "" {
+				out.WriteString("\n")
+			} else if converted, cerr := convert(input); cerr != nil {
+				fail(lineNo, cerr)
+			} else {
+				out.WriteString(converted + "\n")
+			}
+			lineNo++
+		}
+		line = line[:0]
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		// Keep interactive pipes (tail -f) responsive
+		if reader.Buffered() == 0 {
+			if err := out.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := out.Flush(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d line(s) could not be converted", failed)
+	}
+	return nil
+}
+
+// runConvertLines converts one timestamp per line read from r
+func runConvertLines(r io.Reader, w io.Writer, limit int, format convertLine) error {
+	return runLines(r, w, limit, func(input string) (string, error) {
+		epochMs, err := parseEpoch(input)
+		if err != nil {
+			return "", fmt.Errorf("invalid timestamp %q", input)
+		}
+		return format(input, epochMs)
+	})
+}
+
+// pairColumns is a flag.Value accepting the 1-based start and end columns
+// of --pair-columns, e.g. "1,2"
+type pairColumns [2]int
+
+func (p *pairColumns) String() string {
+	if p[0] == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d,%d", p[0], p[1])
+}
+
+func (p *pairColumns) Set(s string) error {
+	start, end, ok := strings.Cut(s, ",")
+	var err error
+	if ok {
+		if p[0], err = strconv.Atoi(strings.TrimSpace(start)); err == nil {
+			p[1], err = strconv.Atoi(strings.TrimSpace(end))
+		}
+	}
+	if !ok || err != nil || p[0] < 1 || p[1] < 1 || p[0] == p[1] {
+		return errors.New("expected two distinct column numbers such as 1,2")
+	}
+	return nil
+}
+
+// splitColumns splits a line on tabs when it has any, else on whitespace
+func splitColumns(line string) []string {
+	if strings.Contains(line, "\t") {
+		return strings.Split(line, "\t")
+	}
+	return strings.Fields(line)
+}
+
+// runPairLines reads a start and an end timestamp from the given columns
+// of every line and writes the duration between them: humanized on a
+// terminal, milliseconds and humanized separated by a tab when piped
+func runPairLines(r io.Reader, w io.Writer, limit int, cols pairColumns, precision int, tty bool) error {
+	f := newFormatter(precision)
+	return runLines(r, w, limit, func(input string) (string, error) {
+		fields := splitColumns(input)
+		if len(fields) < max(cols[0], cols[1]) {
+			return "", fmt.Errorf("expected at least %d columns, got %d", max(cols[0], cols[1]), len(fields))
+		}
+		var epochs [2]int64
+		for i, col := range cols {
+			value := strings.TrimSpace(fields[col-1])
+			epochMs, err := parseEpoch(value)
+			if err != nil {
+				return "", fmt.Errorf("invalid timestamp %q in column %d", value, col)
+			}
+			epochs[i] = epochMs
+		}
+
+		d := time.Duration(epochs[1]-epochs[0]) * time.Millisecond
+		human := f.Duration(d)
+		if d < 0 {
+			human = "-" + human
+		}
+		if tty {
+			return human, nil
+		}
+		return fmt.Sprintf("%d\t%s", d.Milliseconds(), human), nil
+	})
+}
+
+// batchFormat returns the per-line format of convert --stdin: labeled on a
+// terminal, epoch and relative time separated by a tab when piped
+func batchFormat(precision int, aria, tty bool) convertLine {
+	return func(_ string, epochMs int64) (string, error) {
+		switch {
+		case aria:
+			return ariaFragment(epochMs, precision), nil
+		case tty:
+			return fmt.Sprintf("%d  %s UTC  %s", epochMs, formatDateTime(time.UnixMilli(epochMs), true), timeAgo(epochMs, precision)), nil
+		default:
+			return fmt.Sprintf("%d\t%s", emitEpoch(epochMs), timeAgo(epochMs, precision)), nil
+		}
+	}
+}
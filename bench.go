@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// benchDescription details the bench command for its usage
+const benchDescription = `Runs each operation repeatedly for --duration and reports its throughput
+and allocations on this machine. "humanize" and "duration" follow --style,
+--lang and --precision. Piped output is one tab separated line per case:
+name, ops/sec, ns/op, allocs/op and bytes/op.`
+
+// benchCase is one measured operation
+type benchCase struct {
+	name, summary string
+	op            func()
+}
+
+// benchResult is the measurement of one case
+type benchResult struct {
+	ops           int
+	elapsed       time.Duration
+	allocs, bytes uint64
+}
+
+// benchSink keeps the results of the operations alive
+var benchSink any
+
+// benchCases returns the operations measured by bench, relative to now
+func benchCases(precision int, now time.Time) []benchCase {
+	f := newFormatter(precision)
+	past := now.Add(-(3*time.Hour + 25*time.Minute)).UnixMilli()
+	line := now.Add(-90*time.Second).UTC().Format("2006-01-02T15:04:05.000Z") + " INFO GET /index.html 200 12ms"
+	opts := filterOptions{precision: precision, detectors: defaultDetectors, since: -1, until: -1}
+	return []benchCase{
+		{"humanize", "epoch ms to relative time", func() {
+			benchSink = timeAgoAt(past, precision, now)
+		}},
+		{"duration", "time.Duration to words", func() {
+			benchSink = f.Duration(49*time.Hour + 12*time.Minute)
+		}},
+		{"parse-duration", `"2h 30m"`, func() {
+			benchSink, _ = timeago.ParseDuration("2h 30m")
+		}},
+		{"parse-time", `"3 days ago"`, func() {
+			benchSink, _ = timeago.ParseTime("3 days ago", now)
+		}},
+		{"parse-date", `"2024-02-29 13:45"`, func() {
+			benchSink, _ = timeago.ParseDate("2024-02-29 13:45", time.UTC)
+		}},
+		{"filter-line", "one log line through filter", func() {
+			benchSink = rewriteLine(line, findTimestamps(line, opts.detectors), opts)
+		}},
+	}
+}
+
+// measure runs op in growing batches until d has elapsed
+func measure(op func(), d time.Duration) benchResult {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	var r benchResult
+	start := time.Now()
+	for batch := 1; r.elapsed < d; batch = min(batch*2, 1<<20) {
+		for range batch {
+			op()
+		}
+		r.ops += batch
+		r.elapsed = time.Since(start)
+	}
+	runtime.ReadMemStats(&after)
+	r.allocs = after.Mallocs - before.Mallocs
+	r.bytes = after.TotalAlloc - before.TotalAlloc
+	return r
+}
+
+// runBenchCommand measures the formatting and parsing throughput
+func runBenchCommand(args []string) error {
+	fs := newFlagSet("bench")
+	out := addOutputFlags(fs, 2)
+	d := time.Second
+	fs.Var(durationValue{&d}, "duration", "how long to run each case")
+	only := fs.String("only", "", "comma-separated cases to run (default: all)")
+	positional, err := parseArgs("bench", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return errors.New("bench takes no arguments")
+	}
+	if d <= 0 {
+		return errors.New("--duration must be positive")
+	}
+
+	cases := benchCases(out.precision, clock.Now())
+	if *only != "" {
+		names := strings.Split(*only, ",")
+		for _, name := range names {
+			if !slices.ContainsFunc(cases, func(c benchCase) bool { return c.name == name }) {
+				return fmt.Errorf("unknown bench case %q", name)
+			}
+		}
+		cases = slices.DeleteFunc(cases, func(c benchCase) bool { return !slices.Contains(names, c.name) })
+	}
+
+	tty := isTTY()
+	if tty {
+		fmt.Printf("Go %s %s/%s, %d CPUs, %s per case\n\n", runtime.Version(), runtime.GOOS, runtime.GOARCH, runtime.NumCPU(), d)
+		fmt.Printf("%-15s %12s %10s %10s %9s  %s\n", "CASE", "OPS/SEC", "NS/OP", "ALLOCS/OP", "B/OP", "INPUT")
+	}
+	for _, c := range cases {
+		r := measure(c.op, d)
+		opsPerSec := float64(r.ops) / r.elapsed.Seconds()
+		nsPerOp := float64(r.elapsed.Nanoseconds()) / float64(r.ops)
+		allocs := float64(r.allocs) / float64(r.ops)
+		bytes := float64(r.bytes) / float64(r.ops)
+		if tty {
+			fmt.Printf("%-15s %12.0f %10.1f %10.1f %9.0f  %s\n", c.name, opsPerSec, nsPerOp, allocs, bytes, c.summary)
+		} else {
+			fmt.Printf("%s\t%.0f\t%.1f\t%.1f\t%.0f\n", c.name, opsPerSec, nsPerOp, allocs, bytes)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// bucketDescription details the bucket command for its usage
+const bucketDescription = `Hashes INPUT (a host name, an ID or a timestamp, read as text) with
+FNV-1a into one of --slots equal slots of the current --period, so every
+machine computes the same schedule without coordination. Slots are
+numbered from 1. Piped output is "SLOT START END".`
+
+// bucketSlot returns the 0-based slot of input among n
+func bucketSlot(input string, n int) int {
+	h := fnv.New64a()
+	h.Write([]byte(input))
+	return int(h.Sum64() % uint64(n))
+}
+
+// slotRange returns slot i of n equal slots of r
+func slotRange(r timeago.Range, i, n int) timeago.Range {
+	length := r.End.Sub(r.Start)
+	// length*i/n overflows past about 292 years, so split off the remainder
+	offset := func(i int) time.Duration {
+		return length/time.Duration(n)*time.Duration(i) + length%time.Duration(n)*time.Duration(i)/time.Duration(n)
+	}
+	return timeago.Range{
+		Start: r.Start.Add(offset(i)),
+		End:   r.Start.Add(offset(i + 1)),
+	}
+}
+
+// runBucketCommand assigns an input to a slot of the current period
+func runBucketCommand(args []string) error {
+	loc := time.Local
+	fs := newFlagSet("bucket")
+	slots := fs.Int("slots", 10, "number of slots the period is divided into")
+	unit := fs.String("period", "week", "period divided into slots: day, week, month, quarter or year")
+	offset := fs.Int("offset", 0, "periods to shift by, e.g. 1 for the next one")
+	fs.Var(locationValue{&loc}, "tz", "time zone of the calendar")
+	out := addOutputFlags(fs, 7)
+	positional, err := parseArgs("bucket", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("bucket requires one input, e.g. a host name")
+	}
+	if *slots < 1 {
+		return errors.New("--slots requires a positive number")
+	}
+	r, err := timeago.Period(strings.ToLower(*unit), clock.Now().In(loc), *offset)
+	if err != nil {
+		return fmt.Errorf("unknown period %q (expected day, week, month, quarter or year)", *unit)
+	}
+
+	slot := bucketSlot(positional[0], *slots)
+	sr := slotRange(r, slot, *slots)
+	if !isTTY() {
+		fmt.Printf("%d %d %d\n", slot+1, emitEpoch(sr.Start.UnixMilli()), emitEpoch(sr.End.UnixMilli()))
+		return nil
+	}
+	fmt.Printf("Slot: %d of %d\n", slot+1, *slots)
+	printRange(sr, loc, out.precision)
+	return nil
+}
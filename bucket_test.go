@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+func TestSlotRange(t *testing.T) {
+	year := timeago.Range{
+		Start: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+	length := year.End.Sub(year.Start)
+
+	tests := []struct {
+		name string
+		i, n int
+	}{
+		{"first of ten", 0, 10},
+		{"last of ten", 9, 10},
+		{"uneven split", 2, 7},
+		{"many slots", 11206, 100000},
+		{"last of many", 99999, 100000},
+		{"one per second", 31535999, 31536000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slotRange(year, tt.i, tt.n)
+			// Exact in float64 at these magnitudes to well under a microsecond
+			want := year.Start.Add(time.Duration(float64(length) * float64(tt.i) / float64(tt.n)))
+			if d := got.Start.Sub(want); d < -time.Microsecond || d > time.Microsecond {
+				t.Errorf("slot %d of %d starts at %v, want %v", tt.i, tt.n, got.Start, want)
+			}
+			if !got.End.After(got.Start) {
+				t.Errorf("slot %d of %d is empty: %v to %v", tt.i, tt.n, got.Start, got.End)
+			}
+		})
+	}
+
+	// Consecutive slots tile the period exactly
+	const n = 100000
+	prev := year.Start
+	for i := 0; i < n; i++ {
+		s := slotRange(year, i, n)
+		if !s.Start.Equal(prev) {
+			t.Fatalf("slot %d starts at %v, the previous one ended at %v", i, s.Start, prev)
+		}
+		prev = s.End
+	}
+	if !prev.Equal(year.End) {
+		t.Fatalf("the last slot ends at %v, want %v", prev, year.End)
+	}
+}
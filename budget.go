@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// readDurations parses one duration per line, skipping blanks and # comments
+func readDurations(r io.Reader) ([]time.Duration, error) {
+	var durations []time.Duration
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		d, err := timeago.ParseDuration(translateInput(line))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", lineNo, describeParseError(err))
+		}
+		durations = append(durations, d)
+	}
+	return durations, scanner.Err()
+}
+
+// runBudgetCommand subtracts the spent durations from a total budget and
+// reports what is left. The spent list is comma separated, or read from
+// stdin (one duration per line) when --spent is "-", "stdin" or omitted.
+func runBudgetCommand(args []string) error {
+	fs := newFlagSet("budget")
+	out := addOutputFlags(fs, 7)
+	spentArg := fs.String("spent", "-", "comma-separated durations, or - to read them from stdin")
+	positional, err := parseArgs("budget", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return fmt.Errorf("budget requires a total (e.g. timeago budget 40h --spent 8h,6h)")
+	}
+	total, err := timeago.ParseDuration(translateInput(positional[0]))
+	if err != nil {
+		return errors.New(describeParseError(err))
+	}
+
+	var entries []time.Duration
+	if *spentArg == "-" || *spentArg == "stdin" {
+		entries, err = readDurations(os.Stdin)
+		if err != nil {
+			return err
+		}
+	} else {
+		for _, item := range strings.Split(*spentArg, ",") {
+			d, err := timeago.ParseDuration(translateInput(strings.TrimSpace(item)))
+			if err != nil {
+				return errors.New(describeParseError(err))
+			}
+			entries = append(entries, d)
+		}
+	}
+
+	var spent time.Duration
+	for _, d := range entries {
+		spent += d
+	}
+	remaining := total - spent
+
+	if !isTTY() {
+		fmt.Println(remaining.Milliseconds())
+		return nil
+	}
+
+	f := hoursFormatter(out.precision)
+	fmt.Printf("Budget: %s\n", f.Duration(total))
+	fmt.Printf("Spent: %s (%d entries)\n", f.Duration(spent), len(entries))
+	if remaining >= 0 {
+		fmt.Printf("Remaining: %s\n", f.Duration(remaining))
+	} else {
+		fmt.Printf("Over budget: %s\n", f.Duration(remaining))
+	}
+	if total > 0 {
+		fmt.Printf("Used: %.1f%%\n", float64(spent)/float64(total)*100)
+	}
+	return nil
+}
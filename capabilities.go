@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// capabilitiesDescription details the capabilities command for its usage
+const capabilitiesDescription = `Lets wrapper tools and editors adapt to the installed binary. --json
+prints one object with the keys commands, input_formats, output_formats,
+styles, locales, units, epoch_units, zone_database and go_version.`
+
+// inputFormats name the timestamp and duration syntaxes parseEpoch and
+// ParseDuration accept
+var inputFormats = []string{
+	"epoch", "iso8601", "rfc3339", "git-iso", "date", "phrase", "expression",
+	"duration", "iso8601-duration", "go-duration",
+}
+
+// outputFormats name the renderings the commands can produce
+var outputFormats = []string{
+	"text", "tsv", "json", "ndjson", "html", "markdown", "sql", "strftime", "go-layout", "template",
+}
+
+// capabilities describes this build for the capabilities command
+type capabilities struct {
+	Commands      []string `json:"commands"`
+	InputFormats  []string `json:"input_formats"`
+	OutputFormats []string `json:"output_formats"`
+	Styles        []string `json:"styles"`
+	Locales       []string `json:"locales"`
+	Units         []string `json:"units"`
+	EpochUnits    []string `json:"epoch_units"`
+	ZoneDatabase  string   `json:"zone_database"` // "system" or "embedded"
+	GoVersion     string   `json:"go_version"`
+}
+
+// runCapabilitiesCommand lists what this build supports
+func runCapabilitiesCommand(args []string) error {
+	fs := newFlagSet("capabilities")
+	asJSON := fs.Bool("json", false, "print a JSON object")
+	positional, err := parseArgs("capabilities", fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return errors.New("capabilities takes no arguments")
+	}
+
+	caps := capabilities{
+		InputFormats:  inputFormats,
+		OutputFormats: outputFormats,
+		Styles:        outputStyles,
+		Locales:       timeago.LocaleNames(),
+		Units:         timeago.UnitWords(),
+		EpochUnits:    epochUnits,
+		ZoneDatabase:  "embedded",
+		GoVersion:     runtime.Version(),
+	}
+	for _, c := range commands() {
+		caps.Commands = append(caps.Commands, c.name)
+	}
+	if systemZoneinfo() {
+		caps.ZoneDatabase = "system"
+	}
+
+	if *asJSON {
+		data, err := json.MarshalIndent(caps, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	fmt.Printf("Commands: %s\n", strings.Join(caps.Commands, ", "))
+	fmt.Printf("Input formats: %s\n", strings.Join(caps.InputFormats, ", "))
+	fmt.Printf("Output formats: %s\n", strings.Join(caps.OutputFormats, ", "))
+	fmt.Printf("Styles: %s\n", strings.Join(caps.Styles, ", "))
+	fmt.Printf("Locales: %s\n", strings.Join(caps.Locales, ", "))
+	fmt.Printf("Units: %s\n", strings.Join(caps.Units, ", "))
+	fmt.Printf("Epoch units: %s\n", strings.Join(caps.EpochUnits, ", "))
+	fmt.Printf("Zone database: %s\n", caps.ZoneDatabase)
+	fmt.Printf("Go version: %s\n", caps.GoVersion)
+	return nil
+}
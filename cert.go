@@ -0,0 +1,118 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// certDescription details the cert command for its usage
+const certDescription = `Connects over TLS (port 443 unless given) and reads the validity of the
+leaf certificate, whether or not it verifies, so expired certificates are
+reported instead of failing the handshake. Exits with status 2 when it
+expires within --warn, has expired or is not valid yet. Piped output is
+the NotAfter epoch.`
+
+// fetchLeaf returns the certificate chain presented by addr for serverName
+func fetchLeaf(addr, serverName string, timeout time.Duration) ([]*x509.Certificate, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{
+		ServerName: serverName,
+		// Verified separately, so an expired certificate is still read
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	chain := conn.ConnectionState().PeerCertificates
+	if len(chain) == 0 {
+		return nil, errors.New("no certificate presented")
+	}
+	return chain, nil
+}
+
+// verifyChain checks the chain against the system roots for serverName at
+// the instant now
+func verifyChain(chain []*x509.Certificate, serverName string, now time.Time) error {
+	intermediates := x509.NewCertPool()
+	for _, c := range chain[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err := chain[0].Verify(x509.VerifyOptions{DNSName: serverName, Intermediates: intermediates, CurrentTime: now})
+	return err
+}
+
+// runCertCommand reports the validity of a server's TLS certificate
+func runCertCommand(args []string) error {
+	fs := newFlagSet("cert")
+	out := addOutputFlags(fs, 2)
+	warn := 30 * 24 * time.Hour
+	fs.Var(durationValue{&warn}, "warn", "exit with status 2 when expiring within this duration")
+	timeout := 10 * time.Second
+	fs.Var(durationValue{&timeout}, "timeout", "timeout of the connection and handshake")
+	serverName := fs.String("servername", "", "name sent in SNI and checked against the certificate (default: the host)")
+	positional, err := parseArgs("cert", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("cert requires one HOST[:PORT]")
+	}
+
+	addr := positional[0]
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = strings.Trim(addr, "[]")
+		addr = net.JoinHostPort(host, "443")
+	}
+	if *serverName == "" {
+		*serverName = host
+	}
+
+	chain, err := fetchLeaf(addr, *serverName, timeout)
+	if err != nil {
+		return fmt.Errorf("cannot read the certificate of %s: %s", addr, err)
+	}
+	leaf := chain[0]
+	now := clock.Now()
+	remaining := leaf.NotAfter.Sub(now)
+	notYetValid := now.Before(leaf.NotBefore)
+
+	if isTTY() {
+		fmt.Printf("Host: %s\n", addr)
+		fmt.Printf("Subject: %s\n", leaf.Subject.CommonName)
+		fmt.Printf("Issuer: %s\n", leaf.Issuer.CommonName)
+		fmt.Printf("Valid from: %s (%s)\n", formatDateTime(leaf.NotBefore.Local(), false), timeAgo(leaf.NotBefore.UnixMilli(), out.precision))
+		fmt.Printf("Expires: %s (%s)\n", formatDateTime(leaf.NotAfter.Local(), false), timeAgo(leaf.NotAfter.UnixMilli(), out.precision))
+		if err := verifyChain(chain, *serverName, now); err != nil {
+			fmt.Printf("Trust: NOT TRUSTED (%s)\n", err)
+		} else {
+			fmt.Println("Trust: OK")
+		}
+		switch {
+		case notYetValid:
+			fmt.Println("Status: NOT YET VALID")
+		case remaining <= 0:
+			fmt.Println("Status: EXPIRED")
+		case remaining <= warn:
+			fmt.Printf("Status: WARNING, expires within %s\n", newFormatter(out.precision).Duration(warn))
+		default:
+			fmt.Println("Status: OK")
+		}
+	} else {
+		fmt.Println(emitEpoch(leaf.NotAfter.UnixMilli()))
+	}
+
+	if notYetValid || remaining <= warn {
+		return exitStatus(2)
+	}
+	return nil
+}
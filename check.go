@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// checkDescription details the check command for its usage
+const checkDescription = `Exits like test(1), so scripts can branch without parsing output:
+  0  every given condition holds
+  1  a condition does not hold
+  2  invalid arguments, e.g. a timestamp that cannot be read
+  if timeago check "$ts" --within 1h; then ...
+--within compares the distance to now in either direction.`
+
+// runCheckCommand runs check, reporting errors with status 2 so they are
+// never mistaken for a condition that does not hold
+func runCheckCommand(args []string) error {
+	err := checkConditions(args)
+	var status exitStatus
+	if err == nil || errors.As(err, &status) || errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	return statusError{err: err, status: 2}
+}
+
+// checkConditions compares a timestamp against others or against a window
+// around now
+func checkConditions(args []string) error {
+	fs := newFlagSet("check")
+	before := fs.String("before", "", "holds when TIMESTAMP is earlier than this timestamp")
+	after := fs.String("after", "", "holds when TIMESTAMP is later than this timestamp")
+	var within time.Duration
+	fs.Var(durationValue{&within}, "within", "holds when TIMESTAMP is at most this far from now")
+	var now string
+	fs.Var(nowValue{&now}, "now", nowUsage)
+	positional, err := parseArgs("check", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := setNow(now); err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return errors.New("check requires a timestamp")
+	}
+	if within < 0 {
+		return errors.New("--within requires a positive duration")
+	}
+	if *before == "" && *after == "" && within == 0 {
+		return errors.New("check requires --before, --after or --within")
+	}
+	input := strings.Join(positional, " ")
+	epochMs, err := parseEpoch(input)
+	if err != nil {
+		return fmt.Errorf("Invalid timestamp %q", input)
+	}
+	t := time.UnixMilli(epochMs)
+
+	type condition struct {
+		label string
+		holds bool
+	}
+	var conditions []condition
+	for _, c := range []struct {
+		name, value string
+		holds       func(time.Time) bool
+	}{
+		{"before", *before, t.Before},
+		{"after", *after, t.After},
+	} {
+		if c.value == "" {
+			continue
+		}
+		refMs, err := parseEpoch(c.value)
+		if err != nil {
+			return fmt.Errorf("--%s: invalid timestamp %q", c.name, c.value)
+		}
+		ref := time.UnixMilli(refMs)
+		conditions = append(conditions, condition{c.name + " " + formatDateTime(ref, false), c.holds(ref)})
+	}
+	if within > 0 {
+		since := clock.Now().Sub(t)
+		distance := since.Abs()
+		conditions = append(conditions, condition{"within " + newFormatter(2).Duration(within) + " of now", distance <= within})
+	}
+
+	allHold := true
+	for _, c := range conditions {
+		if isTTY() {
+			answer := "yes"
+			if !c.holds {
+				answer = "no"
+			}
+			fmt.Printf("%s %s: %s\n", formatDateTime(t, false), c.label, answer)
+		}
+		allHold = allHold && c.holds
+	}
+	if !allHold {
+		return exitStatus(1)
+	}
+	return nil
+}
@@ -0,0 +1,504 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// command is a timeago subcommand with its own flag set
+type command struct {
+	name        string
+	args        string // synopsis of the positional arguments
+	summary     string // one line for the command list
+	description string // details shown by "timeago <command> -h"
+	run         func(args []string) error
+}
+
+// commands returns the subcommands in the order they are listed in help
+func commands() []command {
+	return []command{
+		{"now", "", "Show the current time in epoch, UTC and local formats", "", runNow},
+		{"convert", "<TIMESTAMP> [PRECISION]", "Show a timestamp in multiple formats with relative time", "", runConvert},
+		{"all", "[TIMESTAMP]", "Show every format of one instant at once", allDescription, runAllCommand},
+		{"parse", "<PHRASE>", "Turn a relative phrase such as \"2 hours ago\" into an epoch", parseDescription, runParseCommand},
+		{"add", "<TIME> [TIMESTAMP] [PRECISION]", "Add time to now or to a timestamp", `TIME: human-readable time (e.g. "2 hours", "30 minutes", "1 day 5 hours")`, runAdd},
+		{"sub", "<TIME> [TIMESTAMP] [PRECISION]", "Remove time from now or from a timestamp", `TIME: human-readable time (e.g. "2 hours", "30 minutes", "1 day 5 hours")`, runSub},
+		{"diff", "<FROM> <TO> [PRECISION]", "Show the signed difference between two timestamps", "The difference is TO minus FROM, in milliseconds and in human-readable units.", runDiffCommand},
+		{"duration", "<DURATION>", "Convert a duration to a unit or break it down", durationDescription, runDurationCommand},
+		{"normalize", "[DURATION...]", "Rewrite durations in their largest units", normalizeDescription, runNormalizeCommand},
+		{"check", "<TIMESTAMP>", "Exit 0, 1 or 2 (error) on comparisons, like test(1)", checkDescription, runCheckCommand},
+		{"zones", "[TIMESTAMP]", "Show one instant in many time zones at once", "Lists each zone's date, time, offset and day difference to the local date.", runZonesCommand},
+		{"when", "<TIME ZONE> [--in ZONE]...", "Show a wall-clock time elsewhere in other zones", whenDescription, runWhenCommand},
+		{"filter", "", "Humanize timestamps in log lines read from stdin", filterDescription, runFilterCommand},
+		{"history-annotate", "", "Prefix shell history read from stdin with each command's age", historyDescription, runHistoryAnnotateCommand},
+		{"packages", "", "Humanize rpm/dpkg build and install dates read from stdin", packagesDescription, runPackagesCommand},
+		{"json", "", "Convert a JSON array of timestamps read from stdin", "Writes a JSON array (or NDJSON with --ndjson) of conversion objects.", runJSONCommand},
+		{"shift", "--by <OFFSET>", "Shift every timestamp read from stdin by a fixed offset", "Keeps the original format of each timestamp (e.g. --by -37d4h to anonymize log samples).", runShiftCommand},
+		{"remaining", "<TIMESTAMP>", "Report the time left until a timestamp, for polling loops", remainingDescription, runRemainingCommand},
+		{"mark", "<NAME>", "Store the current time under a name", markDescription, runMarkCommand},
+		{"elapsed", "<NAME>", "Report the time since a mark, across invocations", "Piped output is the elapsed milliseconds.\n" + markDescription, runElapsedCommand},
+		{"golden", "", "Render a fixed battery of inputs for snapshot tests", goldenDescription, runGoldenCommand},
+		{"assert-window", "", "Fail outside allowed days and hours, e.g. business hours", windowDescription, runAssertWindowCommand},
+		{"window", "--every <N> --per <DURATION>", "Report when a rate-limit window resets and the requests left", rateWindowDescription, runRateWindowCommand},
+		{"cron", "explain <EXPRESSION>", "Describe a cron expression and list its next runs", cronDescription, runCronCommand},
+		{"range", "<PHRASE>", "Resolve a phrase like \"last week\" into start and end epochs", rangeDescription, runRangeCommand},
+		{"day", "[DATE]", "Print the exact start and end of a local calendar day", dayDescription, runDayCommand},
+		{"period", "<day|week|month|quarter|year>", "Print the start and end of the current calendar period", "Weeks start on Monday; --offset -1 selects the previous period. Piped output is \"START END\".", runPeriodCommand},
+		{"bucket", "<INPUT>", "Assign an input to a stable slot of the current period", bucketDescription, runBucketCommand},
+		{"sql", "<PHRASE>", "Print a SQL WHERE condition selecting a range", "DIALECT: postgres (default), mysql, sqlite, bigquery (literals in UTC)", runSQLCommand},
+		{"budget", "<TOTAL>", "Subtract spent durations from a budget", "Spent durations are comma separated, or read one per line from stdin.", runBudgetCommand},
+		{"worklog", "", "Total \"start end [label]\" lines read from stdin per label", "start/end: epoch ms, ISO 8601, or HH:MM clock times", runWorklogCommand},
+		{"report", "--period <day|week|month|quarter|year>", "Summarize piped intervals or events per period as Markdown", reportDescription, runReportCommand},
+		{"pomodoro", "", "Run timed work/break cycles with notifications", "--exec runs at every phase with TIMEAGO_PHASE (work, break, done) and TIMEAGO_CYCLE set.", runPomodoroCommand},
+		{"alarm", "<HH:MM[:SS]> [-- COMMAND [ARGS...]]", "Wait for the next occurrence of a wall-clock time", "Notifies and runs COMMAND when the time is reached (today, or tomorrow if already passed).", runAlarmCommand},
+		{"until", "<TARGET>", "Block until a timestamp or for a duration, then exit", untilDescription, runUntilCommand},
+		{"daemon", "", "Hold named timers in the background over a local socket", daemonDescription, runDaemonCommand},
+		{"timer", "<add|list|cancel> [NAME] [TARGET]", "Manage the named timers of a running daemon", timerDescription, runTimerCommand},
+		{"shell-init", "<zsh|bash|fish>", "Print shell hooks reporting how long each command took", shellInitDescription, runShellInitCommand},
+		{"bench", "", "Measure formatting and parsing throughput", benchDescription, runBenchCommand},
+		{"capabilities", "", "List the formats, styles, locales and units of this build", capabilitiesDescription, runCapabilitiesCommand},
+		{"complete-arg", "<PARTIAL>", "Print completion candidates for shell completion scripts", completeDescription, runCompleteArgCommand},
+		{"file", "<PATH>", "Show the modification, access, change and birth times of a file", fileDescription, runFileCommand},
+		{"pid", "<PID>", "Show when a process started and how long it has been running", pidDescription, runPidCommand},
+		{"ls", "[DIR]", "List directory entries with their modification ages", lsDescription, runLsCommand},
+		{"archive", "<FILE>", "List tar/zip entries with humanized mtimes", "Flags entries with zero or future timestamps. Supports .tar, .tar.gz, .tgz, .tar.bz2 and .zip.", runArchiveCommand},
+		{"jwt", "<TOKEN|->", "Show when a JWT was issued and expires", jwtDescription, runJWTCommand},
+		{"uuid", "<UUID>...", "Show when a time-based UUID (v1, v6, v7) was created", uuidDescription, runUUIDCommand},
+		{"ulid", "<ULID>... | --stdin", "Show when a ULID was generated", ulidDescription, runULIDCommand},
+		{"exif", "<IMAGE.jpg>", "Report the EXIF capture time of an image", "Includes the GPS time and the delta to the file modification time.", runExifCommand},
+		{"http", "<URL|HEADER>...", "Report HTTP date headers as relative times", httpDescription, runHTTPCommand},
+		{"url", "<URL>...", "Report a server's date headers and clock skew", urlDescription, runURLCommand},
+		{"image", "<REF>", "Report when a container image and its layers were built", imageDescription, runImageCommand},
+		{"cert", "<HOST[:PORT]>", "Show when a server's TLS certificate expires", certDescription, runCertCommand},
+		{"ntp", "[SERVER[:PORT]]", "Query an NTP server and show the local clock offset", ntpDescription, runNTPCommand},
+		{"domain", "<DOMAIN>", "Report the time left before a domain registration expires", domainDescription, runDomainCommand},
+		{"eol", "<PRODUCT> [CYCLE]", "Report the time until or since end of life of a release", eolDescription, runEOLCommand},
+		{"serve", "", "Serve conversions over HTTP", serveDescription, runServeCommand},
+	}
+}
+
+// exitStatus is returned by commands that report a condition through their
+// exit code alone, after printing their regular output
+type exitStatus int
+
+func (s exitStatus) Error() string {
+	return fmt.Sprintf("exit status %d", int(s))
+}
+
+// statusError is an error reported with an exit status other than 1, for
+// commands whose status 1 already means a negative answer
+type statusError struct {
+	err    error
+	status int
+}
+
+func (e statusError) Error() string {
+	return e.err.Error()
+}
+
+// findCommand looks a subcommand up by name
+func findCommand(name string) (command, bool) {
+	for _, c := range commands() {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// newFlagSet creates the flag set of a subcommand. Parse errors are returned
+// rather than printed, and -h prints the usage to stdout.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {}
+	return fs
+}
+
+// printUsage prints the usage of a subcommand and its flags
+func printUsage(c command, fs *flag.FlagSet) {
+	fmt.Printf("%s\n\nUSAGE:\n  timeago %s [OPTIONS] %s\n", c.summary, c.name, c.args)
+	if c.description != "" {
+		fmt.Printf("\n%s\n", c.description)
+	}
+	fmt.Println("\nOPTIONS:")
+	fs.SetOutput(os.Stdout)
+	fs.PrintDefaults()
+}
+
+// parseArgs parses flags found anywhere among args, so options may follow
+// positional arguments. Everything after "--" is positional.
+func parseArgs(name string, fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional, tail []string
+	for i, arg := range args {
+		if arg == "--" {
+			tail = args[i+1:]
+			args = args[:i]
+			break
+		}
+	}
+
+	for {
+		// A negative value ("-30 minutes") would be taken for a flag, so
+		// parse up to it and keep it as a positional argument
+		i := negativeArg(fs, args)
+		if err := fs.Parse(args[:i]); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				if c, ok := findCommand(name); ok {
+					printUsage(c, fs)
+				}
+				return nil, err
+			}
+			return nil, fmt.Errorf("%s (run \"timeago %s -h\" for usage)", err, name)
+		}
+		if rest := fs.Args(); len(rest) > 0 {
+			positional = append(positional, rest[0])
+			args = slices.Concat(rest[1:], args[i:])
+			continue
+		}
+		if i == len(args) {
+			break
+		}
+		positional = append(positional, args[i])
+		args = args[i+1:]
+	}
+	return append(positional, tail...), nil
+}
+
+// negativeNumber matches arguments starting with a negative number
+var negativeNumber = regexp.MustCompile(`^-\d`)
+
+// negativeArg returns the index of the first negative number in args that
+// is not the value of a flag, or len(args)
+func negativeArg(fs *flag.FlagSet, args []string) int {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if negativeNumber.MatchString(arg) {
+			return i
+		}
+		if !strings.HasPrefix(arg, "-") || strings.Contains(arg, "=") {
+			continue
+		}
+		f := fs.Lookup(strings.TrimLeft(arg, "-"))
+		if f == nil {
+			continue
+		}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			continue
+		}
+		// The next argument is the flag's value
+		i++
+	}
+	return len(args)
+}
+
+// durationValue is a flag.Value accepting human-readable durations
+type durationValue struct {
+	d *time.Duration
+}
+
+func (v durationValue) String() string {
+	if v.d == nil || *v.d == 0 {
+		return ""
+	}
+	return timeago.NewFormatter().WithPrecision(7).Duration(*v.d)
+}
+
+func (v durationValue) Set(s string) error {
+	d, err := timeago.ParseDuration(s)
+	if err != nil {
+		return errors.New(describeParseError(err))
+	}
+	*v.d = d
+	return nil
+}
+
+// locationValue is a flag.Value accepting IANA time zone names
+type locationValue struct {
+	loc **time.Location
+}
+
+func (v locationValue) String() string {
+	if v.loc == nil || *v.loc == nil {
+		return ""
+	}
+	return (*v.loc).String()
+}
+
+func (v locationValue) Set(s string) error {
+	loc, err := loadLocation(s)
+	if err != nil {
+		return err
+	}
+	*v.loc = loc
+	return nil
+}
+
+// nowValue is a flag.Value keeping the --now timestamp as given. It is only
+// read by setNow once every flag is parsed, so --unit, --epoch-base and
+// --no-prompt apply to it whatever their position.
+type nowValue struct{ raw *string }
+
+func (v nowValue) String() string {
+	if v.raw == nil {
+		return ""
+	}
+	return *v.raw
+}
+
+func (v nowValue) Set(s string) error {
+	*v.raw = s
+	return nil
+}
+
+// setNow fixes clock at the --now timestamp, if one was given
+func setNow(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	epochMs, err := parseEpoch(raw)
+	if err != nil {
+		return fmt.Errorf("--now: invalid timestamp %q", raw)
+	}
+	clock = timeago.FixedClock(time.UnixMilli(epochMs))
+	return nil
+}
+
+// nowUsage is the help of every --now flag
+const nowUsage = "reference instant of relative times and phrases (default: the current time)"
+
+// jitterUsage is the help of every --jitter flag
+const jitterUsage = "delay the result by a random offset in [0, this duration) to stagger jobs across machines"
+
+// randomJitter returns a uniformly random duration in [0, bound), in whole
+// milliseconds
+func randomJitter(bound time.Duration) time.Duration {
+	if bound < time.Millisecond {
+		return 0
+	}
+	return time.Duration(rand.Int64N(bound.Milliseconds())) * time.Millisecond
+}
+
+// zonesValue is a repeatable flag.Value collecting IANA time zones
+type zonesValue []*time.Location
+
+func (v *zonesValue) String() string {
+	var names []string
+	for _, loc := range *v {
+		names = append(names, loc.String())
+	}
+	return strings.Join(names, ",")
+}
+
+func (v *zonesValue) Set(s string) error {
+	loc, err := loadLocation(s)
+	if err != nil {
+		return err
+	}
+	*v = append(*v, loc)
+	return nil
+}
+
+// outputFlags are the formatting flags shared by subcommands
+type outputFlags struct {
+	precision int
+	speech    bool
+	git       bool
+	k8s       bool
+	daily     bool
+	style     string
+	lang      string
+	roundTo   time.Duration
+	unit      string
+	outUnit   string
+	epochBase string
+	now       string
+	noPrompt  bool
+	format    string
+	layout    string
+	set       bool // precision was given explicitly
+	fs        *flag.FlagSet
+}
+
+// outputStyles are the values of --style
+var outputStyles = []string{"long", "short", "fuzzy", "speech", "git", "k8s", "daily"}
+
+// addOutputFlags registers -p/--precision, --lang and the --speech, --git,
+// --k8s and --daily styles on fs
+func addOutputFlags(fs *flag.FlagSet, defaultPrecision int) *outputFlags {
+	o := &outputFlags{fs: fs}
+	fs.IntVar(&o.precision, "p", defaultPrecision, "number of time units to display (1-7)")
+	fs.IntVar(&o.precision, "precision", defaultPrecision, "same as -p")
+	fs.BoolVar(&o.speech, "speech", false, "word relative times for text-to-speech")
+	fs.BoolVar(&o.git, "git", false, "word relative times like git log --date=relative and read epochs in seconds")
+	fs.BoolVar(&o.k8s, "k8s", false, "render ages like kubectl's AGE column (5d17h)")
+	fs.BoolVar(&o.daily, "daily", false, "render whole calendar days only (today, yesterday, 3 days ago)")
+	fs.StringVar(&o.style, "style", "long", "wording of relative times: long, short (2h), fuzzy (about 2 hours), speech, git, k8s or daily")
+	fs.StringVar(&o.unit, "unit", "auto", "unit of integer timestamps: auto, s, ms, us or ns")
+	fs.BoolVar(&o.noPrompt, "no-prompt", false, "never ask which reading of an ambiguous timestamp is meant")
+	fs.StringVar(&o.epochBase, "epoch-base", "", "read integer timestamps as offsets from this timestamp")
+	fs.StringVar(&o.outUnit, "out-unit", "ms", "unit of epochs in piped, batch and JSON output: s, ms, us or ns")
+	fs.StringVar(&o.format, "format", "", "strftime format of absolute times, e.g. %Y-%m-%dT%H:%M:%S%z")
+	fs.StringVar(&o.layout, "layout", "", "Go layout of absolute times, e.g. 2006-01-02T15:04:05Z07:00")
+	fs.Var(durationValue{&o.roundTo}, "round-to", "snap displayed absolute times to the nearest multiple (e.g. 5m); epochs stay exact")
+	fs.Var(nowValue{&o.now}, "now", nowUsage)
+	fs.StringVar(&o.lang, "lang", "en", "language of relative times: "+strings.Join(timeago.LocaleNames(), ", "))
+	return o
+}
+
+// apply validates the flags once parsed and selects the output style
+func (o *outputFlags) apply() error {
+	o.fs.Visit(func(f *flag.Flag) {
+		if f.Name == "p" || f.Name == "precision" {
+			o.set = true
+		}
+	})
+	if o.precision < 1 || o.precision > 7 {
+		return errors.New("-p requires a value between 1 and 7")
+	}
+	if !slices.Contains(outputStyles, o.style) {
+		return fmt.Errorf("unsupported style %q (supported: %s)", o.style, strings.Join(outputStyles, ", "))
+	}
+	styles := 0
+	for style, set := range map[string]bool{"short": o.style == "short", "fuzzy": o.style == "fuzzy", "speech": o.speech || o.style == "speech",
+		"git": o.git || o.style == "git", "k8s": o.k8s || o.style == "k8s", "daily": o.daily || o.style == "daily"} {
+		if set {
+			outputStyle = style
+			styles++
+		}
+	}
+	if styles > 1 {
+		return errors.New("--style, --speech, --git, --k8s and --daily cannot be combined")
+	}
+	if _, ok := timeago.Locales[o.lang]; !ok {
+		return fmt.Errorf("unsupported language %q (supported: %s)", o.lang, strings.Join(timeago.LocaleNames(), ", "))
+	}
+	if outputStyle == "fuzzy" {
+		f, err := loadFuzzy()
+		if err != nil {
+			return err
+		}
+		fuzzyFormatter = f
+	}
+	// Spelled-out numbers and the short, fuzzy, git, kubectl and daily
+	// formats are English only
+	if o.lang != "en" && styles > 0 {
+		return errors.New("--lang cannot be combined with --style, --speech, --git, --k8s or --daily")
+	}
+	outputLang = o.lang
+	roundTo = o.roundTo
+	if o.unit != "auto" && !slices.Contains(epochUnits, o.unit) {
+		return fmt.Errorf("unsupported unit %q (supported: auto, %s)", o.unit, strings.Join(epochUnits, ", "))
+	}
+	epochUnit = o.unit
+	if err := setOutUnit(o.outUnit); err != nil {
+		return err
+	}
+	promptAmbiguous = !o.noPrompt
+	if o.epochBase != "" {
+		base, err := parseEpoch(o.epochBase)
+		if err != nil {
+			return fmt.Errorf("--epoch-base: invalid timestamp %q", o.epochBase)
+		}
+		epochBase = &base
+	}
+	if err := setNow(o.now); err != nil {
+		return err
+	}
+	switch {
+	case o.format != "" && o.layout != "":
+		return errors.New("--format and --layout cannot be combined")
+	case o.format != "":
+		layout, err := timeago.StrftimeLayout(o.format)
+		if err != nil {
+			return fmt.Errorf("--format: %s", err)
+		}
+		dateTimeLayout = layout
+	case o.layout != "":
+		dateTimeLayout = o.layout
+	}
+	return nil
+}
+
+// without returns args minus n elements starting at i
+func without(args []string, i, n int) []string {
+	rest := append([]string{}, args[:i]...)
+	if i+n < len(args) {
+		rest = append(rest, args[i+n:]...)
+	}
+	return rest
+}
+
+// commandIndex returns the index of the subcommand in args when only
+// output options and their values come before it, as in "timeago -p 2
+// convert 1700000000000", or -1
+func commandIndex(args []string) int {
+	fs := newFlagSet("")
+	addOutputFlags(fs, 1)
+	for i := 0; i < len(args); i++ {
+		if _, ok := findCommand(args[i]); ok {
+			return i
+		}
+		name, _, inline := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		f := fs.Lookup(name)
+		if !strings.HasPrefix(args[i], "-") || f == nil {
+			return -1
+		}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); !inline && !(ok && b.IsBoolFlag()) {
+			// The next argument is the flag's value
+			i++
+		}
+	}
+	return -1
+}
+
+// legacyCommand maps the original flat invocation (timeago <EPOCH>,
+// --add/--remove, --filter, --json-in, --golden, --until, --file, --pid, --before/--after/--within) onto the equivalent subcommand
+func legacyCommand(args []string) (string, []string) {
+	if len(args) == 0 {
+		return "now", nil
+	}
+	for i, arg := range args {
+		switch arg {
+		case "--filter":
+			return "filter", without(args, i, 1)
+		case "--json-in":
+			return "json", without(args, i, 1)
+		case "--golden":
+			return "golden", without(args, i, 1)
+		case "--until":
+			return "until", without(args, i, 1)
+		case "--file":
+			return "file", without(args, i, 1)
+		case "--pid":
+			return "pid", without(args, i, 1)
+		case "--before", "--after", "--within":
+			return "check", args
+		}
+	}
+	for i, arg := range args {
+		if arg != "--add" && arg != "--remove" {
+			continue
+		}
+		name := "add"
+		if arg == "--remove" {
+			name = "sub"
+		}
+		if i+1 >= len(args) {
+			return name, without(args, i, 1)
+		}
+		// The time value comes first, the rest keeps its order
+		return name, append([]string{args[i+1]}, without(args, i, 2)...)
+	}
+	return "convert", args
+}
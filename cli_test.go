@@ -0,0 +1,133 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		positional []string
+		precision  int
+		fixed      bool
+	}{
+		{"flags first", []string{"-p", "3", "--fixed", "2 hours", "1700000000000"}, []string{"2 hours", "1700000000000"}, 3, true},
+		{"flags last", []string{"2 hours", "1700000000000", "--fixed", "-p", "3"}, []string{"2 hours", "1700000000000"}, 3, true},
+		{"flags between", []string{"2 hours", "--precision=3", "1700000000000"}, []string{"2 hours", "1700000000000"}, 3, false},
+		{"negative duration", []string{"-30 minutes", "-p", "2"}, []string{"-30 minutes"}, 2, false},
+		{"negative after a flag", []string{"--fixed", "-1h", "1700000000000"}, []string{"-1h", "1700000000000"}, 7, true},
+		{"negative flag value", []string{"-p", "-1", "x"}, []string{"x"}, -1, false},
+		{"everything after --", []string{"2 hours", "--", "-p", "--fixed"}, []string{"2 hours", "-p", "--fixed"}, 7, false},
+		{"no arguments", nil, nil, 7, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := newFlagSet("add")
+			out := addOutputFlags(fs, 7)
+			fixed := fs.Bool("fixed", false, "")
+			positional, err := parseArgs("add", fs, tt.args)
+			if err != nil {
+				t.Fatalf("parseArgs(%q): %v", tt.args, err)
+			}
+			if !slices.Equal(positional, tt.positional) {
+				t.Errorf("parseArgs(%q) = %q, want %q", tt.args, positional, tt.positional)
+			}
+			if out.precision != tt.precision || *fixed != tt.fixed {
+				t.Errorf("parseArgs(%q): -p %d --fixed %t, want %d and %t", tt.args, out.precision, *fixed, tt.precision, tt.fixed)
+			}
+		})
+	}
+
+	fs := newFlagSet("add")
+	addOutputFlags(fs, 7)
+	if _, err := parseArgs("add", fs, []string{"2 hours", "--bogus"}); err == nil {
+		t.Errorf("parseArgs accepted an unknown flag")
+	}
+}
+
+func TestNegativeArg(t *testing.T) {
+	fs := newFlagSet("add")
+	addOutputFlags(fs, 7)
+	fs.Bool("fixed", false, "")
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{[]string{"-30 minutes"}, 0},
+		{[]string{"--fixed", "-1h"}, 1},
+		{[]string{"-p", "-1"}, 2},
+		{[]string{"-p=2", "-1"}, 1},
+		{[]string{"--lang", "es", "-2 horas"}, 2},
+		{[]string{"--unknown", "-1"}, 1},
+		{[]string{"2 hours", "-p", "3"}, 3},
+		{nil, 0},
+	}
+	for _, tt := range tests {
+		if got := negativeArg(fs, tt.args); got != tt.want {
+			t.Errorf("negativeArg(%q) = %d, want %d", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestLegacyCommand(t *testing.T) {
+	tests := []struct {
+		args []string
+		name string
+		rest []string
+	}{
+		{nil, "now", nil},
+		{[]string{"1700000000000"}, "convert", []string{"1700000000000"}},
+		{[]string{"1700000000000", "3"}, "convert", []string{"1700000000000", "3"}},
+		{[]string{"--stdin"}, "convert", []string{"--stdin"}},
+		{[]string{"--add", "2 hours"}, "add", []string{"2 hours"}},
+		{[]string{"--add", "2 hours", "1700000000000", "-p", "2"}, "add", []string{"2 hours", "1700000000000", "-p", "2"}},
+		{[]string{"1700000000000", "--remove", "1d"}, "sub", []string{"1d", "1700000000000"}},
+		{[]string{"--add"}, "add", []string{}},
+		{[]string{"--filter", "--daily"}, "filter", []string{"--daily"}},
+		{[]string{"--json-in"}, "json", []string{}},
+		{[]string{"--golden", "-p", "2"}, "golden", []string{"-p", "2"}},
+		{[]string{"--until", "17:00"}, "until", []string{"17:00"}},
+		{[]string{"--file", "go.mod", "--stat", "mtime"}, "file", []string{"go.mod", "--stat", "mtime"}},
+		{[]string{"--pid", "1"}, "pid", []string{"1"}},
+		{[]string{"1700000000000", "--within", "1h"}, "check", []string{"1700000000000", "--within", "1h"}},
+		{[]string{"1700000000000", "--before", "now"}, "check", []string{"1700000000000", "--before", "now"}},
+	}
+	for _, tt := range tests {
+		name, rest := legacyCommand(tt.args)
+		if name != tt.name || !slices.Equal(rest, tt.rest) {
+			t.Errorf("legacyCommand(%q) = %s %q, want %s %q", tt.args, name, rest, tt.name, tt.rest)
+		}
+	}
+}
+
+func TestCommandIndex(t *testing.T) {
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{nil, -1},
+		{[]string{"convert", "1700000000000"}, 0},
+		{[]string{"convert", "1700000000000", "-p", "2"}, 0},
+		{[]string{"-p", "2", "convert", "1700000000000"}, 2},
+		{[]string{"--lang", "es", "add", "2 horas"}, 2},
+		{[]string{"--lang=es", "--no-prompt", "add", "2 horas"}, 2},
+		{[]string{"--now", "2024-01-01", "convert", "1700000000000"}, 2},
+		{[]string{"--now", "now", "convert"}, 2},
+		{[]string{"--no-prompt", "now"}, 1},
+
+		// Flat invocations stay with legacyCommand
+		{[]string{"1700000000000"}, -1},
+		{[]string{"-p", "2", "1700000000000"}, -1},
+		{[]string{"1700000000000", "--before", "now"}, -1},
+		{[]string{"--add", "2 hours", "now"}, -1},
+		{[]string{"--", "convert"}, -1},
+		{[]string{"-30", "sub"}, -1},
+	}
+	for _, tt := range tests {
+		if got := commandIndex(tt.args); got != tt.want {
+			t.Errorf("commandIndex(%q) = %d, want %d", tt.args, got, tt.want)
+		}
+	}
+}
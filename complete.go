@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// completeDescription details the complete-arg command for its usage
+const completeDescription = `Prints the candidates starting with PARTIAL (ignoring case), one per line,
+for shell completion scripts: IANA zone names, mark names, duration unit
+words and the --style format names. --kind restricts them to one source,
+e.g. complete-arg --kind zone Europe/ while completing --tz.`
+
+// completionSources list the candidates of each --kind of complete-arg
+var completionSources = map[string]func() ([]string, error){
+	"zone": func() ([]string, error) {
+		return zoneNames(), nil
+	},
+	"mark": func() ([]string, error) {
+		path, err := marksPath()
+		if err != nil {
+			return nil, err
+		}
+		marks, err := loadMarks(path)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(marks))
+		for name := range marks {
+			names = append(names, name)
+		}
+		return names, nil
+	},
+	"unit": func() ([]string, error) {
+		return timeago.UnitWords(), nil
+	},
+	"format": func() ([]string, error) {
+		return outputStyles, nil
+	},
+}
+
+// runCompleteArgCommand prints the completion candidates of a partial word
+func runCompleteArgCommand(args []string) error {
+	fs := newFlagSet("complete-arg")
+	kind := fs.String("kind", "all", "candidates to offer: all, zone, mark, unit or format")
+	positional, err := parseArgs("complete-arg", fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		return errors.New("complete-arg takes one partial word")
+	}
+	partial := ""
+	if len(positional) == 1 {
+		partial = strings.ToLower(positional[0])
+	}
+
+	kinds := []string{*kind}
+	if *kind == "all" {
+		kinds = []string{"format", "unit", "mark", "zone"}
+	} else if _, ok := completionSources[*kind]; !ok {
+		return fmt.Errorf("unsupported kind %q (supported: all, zone, mark, unit, format)", *kind)
+	}
+
+	var candidates []string
+	for _, k := range kinds {
+		words, err := completionSources[k]()
+		if err != nil {
+			return err
+		}
+		for _, word := range words {
+			if strings.HasPrefix(strings.ToLower(word), partial) {
+				candidates = append(candidates, word)
+			}
+		}
+	}
+	sort.Strings(candidates)
+	for _, word := range slices.Compact(candidates) {
+		fmt.Println(word)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// config holds the user settings read from the config file
+type config struct {
+	Detectors []detectorConfig `json:"detectors"`
+	Fuzzy     fuzzyConfig      `json:"fuzzy"`
+	Zones     []string         `json:"zones"`
+}
+
+// fuzzyConfig overrides the thresholds of --style fuzzy; unset fields keep
+// the defaults of timeago.NewFuzzy
+type fuzzyConfig struct {
+	Over    *float64 `json:"over"`
+	RoundUp *float64 `json:"round_up"`
+	Almost  *float64 `json:"almost"`
+}
+
+// detectorConfig describes a user-defined timestamp detector. When the
+// pattern has a capture group, only the first group is taken as the timestamp.
+type detectorConfig struct {
+	Name    string   `json:"name"`
+	Pattern string   `json:"pattern"`
+	Layouts []string `json:"layouts"`
+}
+
+// configPath returns the config file location, honoring TIMEAGO_CONFIG
+func configPath() string {
+	if path := os.Getenv("TIMEAGO_CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "timeago", "config.json")
+}
+
+// loadConfig reads the config file; a missing file yields an empty config
+func loadConfig() (config, error) {
+	var cfg config
+	path := configPath()
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config %s: %s", path, err)
+	}
+	return cfg, nil
+}
+
+// compile turns a detector definition into a timestampDetector
+func (d detectorConfig) compile() (timestampDetector, error) {
+	re, err := regexp.Compile(d.Pattern)
+	if err != nil {
+		return timestampDetector{}, fmt.Errorf("detector %q: invalid pattern: %s", d.Name, err)
+	}
+	if len(d.Layouts) == 0 {
+		return timestampDetector{}, fmt.Errorf("detector %q: at least one layout is required", d.Name)
+	}
+
+	layouts := d.Layouts
+	return timestampDetector{
+		name: d.Name,
+		re:   re,
+		parse: func(s string) (time.Time, error) {
+			var err error
+			for _, layout := range layouts {
+				var t time.Time
+				t, err = time.ParseInLocation(layout, s, time.Local)
+				if err == nil {
+					return t, nil
+				}
+			}
+			return time.Time{}, err
+		},
+		format: func(t time.Time, original string) string {
+			// Render with the layout that matched the original text
+			for _, layout := range layouts {
+				if _, err := time.ParseInLocation(layout, original, time.Local); err == nil {
+					return t.Format(layout)
+				}
+			}
+			return t.Format(layouts[0])
+		},
+	}, nil
+}
+
+// loadFuzzy returns the fuzzy formatter with the thresholds of the config
+func loadFuzzy() (*timeago.Fuzzy, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	f := timeago.NewFuzzy()
+	for _, o := range []struct {
+		value  *float64
+		target *float64
+	}{{cfg.Fuzzy.Over, &f.Over}, {cfg.Fuzzy.RoundUp, &f.RoundUp}, {cfg.Fuzzy.Almost, &f.Almost}} {
+		if o.value != nil {
+			*o.target = *o.value
+		}
+	}
+	if err := f.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %s", configPath(), err)
+	}
+	return f, nil
+}
+
+// reloadConfig re-reads the settings a live display depends on, for
+// SIGUSR1; on error the previous settings stay
+func reloadConfig() error {
+	if outputStyle != "fuzzy" {
+		return nil
+	}
+	f, err := loadFuzzy()
+	if err != nil {
+		return err
+	}
+	fuzzyFormatter = f
+	return nil
+}
+
+// loadZones returns the zones of the config, or defaultWorldZones
+func loadZones() ([]string, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if len(cfg.Zones) == 0 {
+		return defaultWorldZones, nil
+	}
+	return cfg.Zones, nil
+}
+
+// loadDetectors returns the user-defined detectors followed by the defaults
+func loadDetectors() ([]timestampDetector, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var detectors []timestampDetector
+	for _, dc := range cfg.Detectors {
+		d, err := dc.compile()
+		if err != nil {
+			return nil, err
+		}
+		detectors = append(detectors, d)
+	}
+	return append(detectors, defaultDetectors...), nil
+}
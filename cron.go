@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// cronDescription details the cron command for its usage
+const cronDescription = `cron explain describes a cron expression in plain English and lists its
+next --count runs in --tz with relative times. The expression is five
+fields (minute hour day-of-month month day-of-week) or a macro such as
+@daily, quoted or not. Piped output is the description, then one epoch per
+line.`
+
+// runCronCommand implements cron explain
+func runCronCommand(args []string) error {
+	loc := time.Local
+	fs := newFlagSet("cron")
+	out := addOutputFlags(fs, 2)
+	count := fs.Int("count", 5, "number of next runs to list")
+	fs.Var(locationValue{&loc}, "tz", "time zone the schedule runs in")
+	positional, err := parseArgs("cron", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) < 2 || positional[0] != "explain" {
+		return errors.New("cron requires explain and an expression, e.g. cron explain \"0 3 * * 1\"")
+	}
+	if *count < 0 {
+		return errors.New("--count cannot be negative")
+	}
+	schedule, err := timeago.ParseCron(strings.Join(positional[1:], " "))
+	if err != nil {
+		return err
+	}
+
+	tty := isTTY()
+	if tty {
+		fmt.Printf("Schedule: %s\n", schedule.Describe())
+	} else {
+		fmt.Println(schedule.Describe())
+	}
+	t := clock.Now().In(loc)
+	for i := 0; i < *count; i++ {
+		if t = schedule.Next(t); t.IsZero() {
+			if tty && i == 0 {
+				fmt.Println("Next: never")
+			}
+			break
+		}
+		switch {
+		case !tty:
+			fmt.Println(emitEpoch(t.UnixMilli()))
+		case i == 0:
+			fmt.Printf("Next: %s (%s)\n", t.Format("Mon 2006-01-02 15:04"), timeAgo(t.UnixMilli(), out.precision))
+		default:
+			fmt.Printf("      %s (%s)\n", t.Format("Mon 2006-01-02 15:04"), timeAgo(t.UnixMilli(), out.precision))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// daemonDescription details the daemon command for its usage
+const daemonDescription = `Keeps named timers in a background process listening on a Unix socket
+(TIMEAGO_SOCKET or the user cache directory), so they outlive the terminal
+that set them. Run it with "nohup timeago daemon &" or as a user service;
+expired timers raise a notification and are logged to stderr.`
+
+// timerDescription details the timer command for its usage
+const timerDescription = `  timer add NAME TARGET   TARGET is a timestamp or a duration from now
+  timer list              piped output is "NAME<TAB>DEADLINE" lines
+  timer cancel NAME
+Timers are held by a running "timeago daemon".`
+
+// daemonTimer is one named countdown
+type daemonTimer struct {
+	Name     string `json:"name"`
+	Deadline int64  `json:"deadline"` // epoch milliseconds
+	Message  string `json:"message,omitempty"`
+}
+
+// daemonRequest is one JSON line sent to the daemon
+type daemonRequest struct {
+	Op    string      `json:"op"` // add, list or cancel
+	Timer daemonTimer `json:"timer"`
+}
+
+// daemonResponse is the daemon's JSON reply
+type daemonResponse struct {
+	Error  string        `json:"error,omitempty"`
+	Timers []daemonTimer `json:"timers,omitempty"`
+}
+
+// socketPath returns the daemon socket location, honoring TIMEAGO_SOCKET
+func socketPath() (string, error) {
+	if path := os.Getenv("TIMEAGO_SOCKET"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "timeago", "daemon.sock"), nil
+}
+
+// daemon holds the pending timers
+type daemon struct {
+	mu     sync.Mutex
+	timers map[string]daemonTimer
+}
+
+// handle applies one request
+func (d *daemon) handle(req daemonRequest) daemonResponse {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	name := req.Timer.Name
+	switch req.Op {
+	case "add":
+		if name == "" {
+			return daemonResponse{Error: "a timer needs a name"}
+		}
+		d.timers[name] = req.Timer
+		return daemonResponse{Timers: []daemonTimer{req.Timer}}
+	case "cancel":
+		timer, ok := d.timers[name]
+		if !ok {
+			return daemonResponse{Error: fmt.Sprintf("no timer named %q", name)}
+		}
+		delete(d.timers, name)
+		return daemonResponse{Timers: []daemonTimer{timer}}
+	case "list":
+		timers := make([]daemonTimer, 0, len(d.timers))
+		for _, timer := range d.timers {
+			timers = append(timers, timer)
+		}
+		sort.Slice(timers, func(i, j int) bool {
+			return timers[i].Deadline < timers[j].Deadline
+		})
+		return daemonResponse{Timers: timers}
+	}
+	return daemonResponse{Error: fmt.Sprintf("unknown operation %q", req.Op)}
+}
+
+// expire removes and returns the timers due at now
+func (d *daemon) expire(now time.Time) []daemonTimer {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var due []daemonTimer
+	for name, timer := range d.timers {
+		if timer.Deadline <= now.UnixMilli() {
+			due = append(due, timer)
+			delete(d.timers, name)
+		}
+	}
+	return due
+}
+
+// sweep fires expired timers until ctx is canceled. Deadlines are compared
+// against the wall clock every second, like waitUntil, so timers survive
+// system suspend.
+func (d *daemon) sweep(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, timer := range d.expire(now) {
+				message := timer.Message
+				if message == "" {
+					message = fmt.Sprintf("Timer %s expired", timer.Name)
+				}
+				fmt.Fprintf(os.Stderr, "%s: timer %s expired\n", formatDateTime(now, false), timer.Name)
+				notify("timeago timer", message, false)
+			}
+		}
+	}
+}
+
+// serveConn answers the single request of a connection
+func (d *daemon) serveConn(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	var req daemonRequest
+	resp := daemonResponse{}
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		resp.Error = fmt.Sprintf("invalid request: %s", err)
+	} else {
+		resp = d.handle(req)
+	}
+	json.NewEncoder(conn).Encode(resp)
+}
+
+// runDaemonCommand listens for timer requests until interrupted
+func runDaemonCommand(args []string) error {
+	fs := newFlagSet("daemon")
+	positional, err := parseArgs("daemon", fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return errors.New("daemon takes no arguments")
+	}
+
+	path, err := socketPath()
+	if err != nil {
+		return err
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return fmt.Errorf("a daemon is already listening on %s", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// A socket left behind by a daemon that did not exit cleanly
+	os.Remove(path)
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+	if err := os.Chmod(path, 0o600); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+
+	d := &daemon{timers: map[string]daemonTimer{}}
+	go d.sweep(ctx)
+	fmt.Fprintf(os.Stderr, "Listening on %s\n", path)
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				// Stopped by a signal, the normal way out of a service
+				return nil
+			}
+			return err
+		}
+		go d.serveConn(conn)
+	}
+}
+
+// callDaemon sends one request to the running daemon
+func callDaemon(req daemonRequest) ([]daemonTimer, error) {
+	path, err := socketPath()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.DialTimeout("unix", path, 2*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("no daemon listening on %s (start one with: timeago daemon)", path)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, err
+	}
+	var resp daemonResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("invalid daemon response: %s", err)
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return resp.Timers, nil
+}
+
+// runTimerCommand adds, lists and cancels the daemon's timers
+func runTimerCommand(args []string) error {
+	fs := newFlagSet("timer")
+	message := fs.String("message", "", "notification text of timer add (default: \"Timer NAME expired\")")
+	positional, err := parseArgs("timer", fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return errors.New("timer requires add, list or cancel")
+	}
+	tty := isTTY()
+
+	switch op, rest := positional[0], positional[1:]; op {
+	case "add":
+		if len(rest) < 2 {
+			return errors.New("timer add requires a name and a timestamp or duration")
+		}
+		target, err := resolveTarget(strings.Join(rest[1:], " "), clock.Now())
+		if err != nil {
+			return err
+		}
+		timers, err := callDaemon(daemonRequest{Op: op, Timer: daemonTimer{Name: rest[0], Deadline: target.UnixMilli(), Message: *message}})
+		if err != nil {
+			return err
+		}
+		if tty {
+			fmt.Printf("Timer %s: %s (%s)\n", timers[0].Name, formatDateTime(target, false), timeAgo(timers[0].Deadline, 2))
+		} else {
+			fmt.Println(emitEpoch(timers[0].Deadline))
+		}
+	case "list":
+		if len(rest) > 0 {
+			return errors.New("timer list takes no arguments")
+		}
+		timers, err := callDaemon(daemonRequest{Op: op})
+		if err != nil {
+			return err
+		}
+		if tty && len(timers) == 0 {
+			fmt.Println("No timers")
+		}
+		for _, timer := range timers {
+			if tty {
+				fmt.Printf("%-16s %s  %s\n", timer.Name, formatDateTime(time.UnixMilli(timer.Deadline), false), timeAgo(timer.Deadline, 2))
+			} else {
+				fmt.Printf("%s\t%d\n", timer.Name, emitEpoch(timer.Deadline))
+			}
+		}
+	case "cancel":
+		if len(rest) != 1 {
+			return errors.New("timer cancel requires one name")
+		}
+		timers, err := callDaemon(daemonRequest{Op: op, Timer: daemonTimer{Name: rest[0]}})
+		if err != nil {
+			return err
+		}
+		if tty {
+			fmt.Printf("Canceled timer %s\n", timers[0].Name)
+		} else {
+			fmt.Println(emitEpoch(timers[0].Deadline))
+		}
+	default:
+		return fmt.Errorf("unknown timer operation %q (expected add, list or cancel)", op)
+	}
+	return nil
+}
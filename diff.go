@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// runDiffCommand prints the signed difference between two timestamps
+func runDiffCommand(args []string) error {
+	fs := newFlagSet("diff")
+	out := addOutputFlags(fs, 2)
+	positional, err := parseArgs("diff", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) < 2 {
+		return errors.New("diff requires two timestamps")
+	}
+	if len(positional) > 2 {
+		legacyPrecision(out, positional[2])
+	}
+
+	var epochs [2]int64
+	for i, arg := range positional[:2] {
+		if epochs[i], err = parseEpoch(arg); err != nil {
+			return fmt.Errorf("Invalid timestamp %q", arg)
+		}
+	}
+	diffMs := epochs[1] - epochs[0]
+
+	if isTTY() {
+		fmt.Printf("From: %d (%s)\n", epochs[0], formatDateTime(time.UnixMilli(epochs[0]), false))
+		fmt.Printf("To: %d (%s)\n", epochs[1], formatDateTime(time.UnixMilli(epochs[1]), false))
+		fmt.Printf("Difference: %d ms\n", diffMs)
+		fmt.Printf("Human: %s\n", signedDuration(newFormatter(out.precision), time.Duration(diffMs)*time.Millisecond))
+	} else {
+		fmt.Println(diffMs)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// domainDescription details the domain command for its usage
+const domainDescription = `Looks the expiry up over RDAP (rdap.org), falling back to WHOIS.
+Exits with status 2 when the domain expires within --warn or has expired.`
+
+// whoisExpiry matches the expiry line of the common WHOIS formats
+var whoisExpiry = regexp.MustCompile(`(?im)^\s*(?:registry expiry date|registrar registration expiration date|expiration date|expiry date|expires on|expires|paid-till)\s*:\s*(.+?)\s*$`)
+
+// whoisRefer matches the referral to the registry's WHOIS server
+var whoisRefer = regexp.MustCompile(`(?im)^\s*(?:refer|whois)\s*:\s*(\S+)\s*$`)
+
+// whoisLayouts are the date formats registries use in WHOIS answers
+var whoisLayouts = []string{
+	time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02",
+	"02-Jan-2006", "2006.01.02", "2006/01/02", "02.01.2006", "2006-01-02 15:04:05 MST",
+}
+
+// domainName matches an ASCII host name; internationalized names are given
+// in their punycode form (xn--...)
+var domainName = regexp.MustCompile(`^(?:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z0-9-]{2,63}$`)
+
+// rdapDomain is the part of an RDAP domain answer the command reads
+type rdapDomain struct {
+	Events []struct {
+		Action string `json:"eventAction"`
+		Date   string `json:"eventDate"`
+	} `json:"events"`
+}
+
+// rdapExpiry queries rdap.org, which redirects to the registry's server
+func rdapExpiry(domain string, timeout time.Duration) (time.Time, error) {
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get("https://rdap.org/domain/" + domain)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return time.Time{}, fmt.Errorf("RDAP answered %s", resp.Status)
+	}
+
+	var answer rdapDomain
+	if err := json.NewDecoder(resp.Body).Decode(&answer); err != nil {
+		return time.Time{}, fmt.Errorf("invalid RDAP answer: %s", err)
+	}
+	for _, e := range answer.Events {
+		if e.Action == "expiration" {
+			return time.Parse(time.RFC3339, e.Date)
+		}
+	}
+	return time.Time{}, errors.New("RDAP answer has no expiration event")
+}
+
+// whoisQuery sends a query to a WHOIS server and returns the answer
+func whoisQuery(server, query string, timeout time.Duration) (string, error) {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(server, "43"), timeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	if _, err := io.WriteString(conn, query+"\r\n"); err != nil {
+		return "", err
+	}
+	answer, err := io.ReadAll(io.LimitReader(conn, 1<<20))
+	return string(answer), err
+}
+
+// parseWhoisDate parses an expiry value from a WHOIS answer. Only the
+// registry layouts are tried: the text comes from a remote server, so it
+// must not reach phrase parsing or the ambiguous epoch prompt.
+func parseWhoisDate(s string) (time.Time, error) {
+	for _, layout := range whoisLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized WHOIS date: %s", s)
+}
+
+// whoisExpiryDate asks IANA for the registry's WHOIS server, then the
+// registry for the domain's expiry
+func whoisExpiryDate(domain string, timeout time.Duration) (time.Time, error) {
+	answer, err := whoisQuery("whois.iana.org", domain, timeout)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if m := whoisRefer.FindStringSubmatch(answer); m != nil {
+		if answer, err = whoisQuery(m[1], domain, timeout); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	m := whoisExpiry.FindStringSubmatch(answer)
+	if m == nil {
+		return time.Time{}, errors.New("WHOIS answer has no expiry date")
+	}
+	return parseWhoisDate(m[1])
+}
+
+// runDomainCommand reports the registration expiry of a domain
+func runDomainCommand(args []string) error {
+	fs := newFlagSet("domain")
+	out := addOutputFlags(fs, 2)
+	warn := 30 * 24 * time.Hour
+	fs.Var(durationValue{&warn}, "warn", "exit with status 2 when expiring within this duration")
+	timeout := 10 * time.Second
+	fs.Var(durationValue{&timeout}, "timeout", "timeout of each lookup")
+	positional, err := parseArgs("domain", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("domain requires one domain name")
+	}
+	domain := strings.TrimSuffix(strings.ToLower(positional[0]), ".")
+	if len(domain) > 253 || !domainName.MatchString(domain) {
+		return fmt.Errorf("invalid domain name %q (internationalized names must be given as punycode, xn--...)", positional[0])
+	}
+
+	source := "RDAP"
+	expiry, err := rdapExpiry(domain, timeout)
+	if err != nil {
+		rdapErr := err
+		source = "WHOIS"
+		if expiry, err = whoisExpiryDate(domain, timeout); err != nil {
+			return fmt.Errorf("cannot find the expiry of %s (RDAP: %s; WHOIS: %s)", domain, rdapErr, err)
+		}
+	}
+
+	remaining := expiry.Sub(clock.Now())
+	if isTTY() {
+		fmt.Printf("Domain: %s\n", domain)
+		fmt.Printf("Expires: %s (%s)\n", formatDateTime(expiry.Local(), false), timeAgo(expiry.UnixMilli(), out.precision))
+		fmt.Printf("Source: %s\n", source)
+		switch {
+		case remaining <= 0:
+			fmt.Println("Status: EXPIRED")
+		case remaining <= warn:
+			fmt.Printf("Status: WARNING, expires within %s\n", newFormatter(out.precision).Duration(warn))
+		default:
+			fmt.Println("Status: OK")
+		}
+	} else {
+		fmt.Println(emitEpoch(expiry.UnixMilli()))
+	}
+
+	if remaining <= warn {
+		return exitStatus(2)
+	}
+	return nil
+}
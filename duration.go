@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// durationDescription details the duration command for its usage
+const durationDescription = `Without --to, breaks the duration down into years (365 days), months (30
+days), weeks, days, hours, minutes, seconds and milliseconds. Piped output
+is the number in --to units, or "unit<TAB>count" lines for the breakdown.`
+
+// breakdownUnits are the units of a duration breakdown, largest first
+var breakdownUnits = append(append([]timeago.Unit{}, timeago.DefaultUnits...),
+	timeago.Unit{Singular: "millisecond", Plural: "milliseconds", Duration: time.Millisecond})
+
+// unitCount is one line of a duration breakdown
+type unitCount struct {
+	unit  timeago.Unit
+	count int64
+}
+
+// breakdown splits d into whole counts of breakdownUnits, skipping zeros;
+// a negative duration yields negative counts
+func breakdown(d time.Duration) []unitCount {
+	sign := int64(1)
+	if d < 0 {
+		sign, d = -1, -d
+	}
+	var counts []unitCount
+	for _, unit := range breakdownUnits {
+		if n := int64(d / unit.Duration); n > 0 {
+			counts = append(counts, unitCount{unit, sign * n})
+			d -= time.Duration(n) * unit.Duration
+		}
+	}
+	return counts
+}
+
+// convertDuration renders d in unit with up to decimals digits, trailing
+// zeros trimmed
+func convertDuration(d, unit time.Duration, decimals int) string {
+	s := new(big.Rat).SetFrac64(int64(d), int64(unit)).FloatString(decimals)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		s = "0"
+	}
+	return s
+}
+
+// runDurationCommand converts a duration to a unit or breaks it down
+func runDurationCommand(args []string) error {
+	fs := newFlagSet("duration")
+	to := fs.String("to", "", "convert to this unit (e.g. minutes, h, ms)")
+	decimals := fs.Int("decimals", 2, "maximum number of decimals with --to")
+	positional, err := parseArgs("duration", fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("duration requires one duration, e.g. \"3 days 4 hours\"")
+	}
+	if *decimals < 0 {
+		return errors.New("--decimals cannot be negative")
+	}
+	d, err := timeago.ParseDuration(translateInput(positional[0]))
+	if err != nil {
+		return errors.New(describeParseError(err))
+	}
+
+	if *to != "" {
+		unit, err := timeago.UnitDuration(*to)
+		if err != nil {
+			return errors.New(describeParseError(err))
+		}
+		value := convertDuration(d, unit, *decimals)
+		if isTTY() {
+			fmt.Printf("%s %s\n", value, *to)
+		} else {
+			fmt.Println(value)
+		}
+		return nil
+	}
+
+	counts := breakdown(d)
+	if len(counts) == 0 {
+		counts = []unitCount{{breakdownUnits[len(breakdownUnits)-1], 0}}
+	}
+	if !isTTY() {
+		for _, c := range counts {
+			fmt.Printf("%s\t%d\n", c.unit.Plural, c.count)
+		}
+		return nil
+	}
+	terms := make([]string, 0, len(counts))
+	for _, c := range counts {
+		name := c.unit.Plural
+		if c.count == 1 || c.count == -1 {
+			name = c.unit.Singular
+		}
+		terms = append(terms, fmt.Sprintf("%d %s", c.count, name))
+	}
+	fmt.Printf("Breakdown: %s\n", strings.Join(terms, " "))
+	fmt.Printf("Milliseconds: %d\n", d.Milliseconds())
+	return nil
+}
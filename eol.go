@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// eolDescription details the eol command for its usage
+const eolDescription = `Looks release cycles up on endoflife.date (e.g. ubuntu, python, nodejs,
+postgresql) and reports the time until or since their end of life. Answers
+are cached for --max-age in the user cache directory; when the site is
+unreachable the cache is used whatever its age, and --offline never asks.
+With a CYCLE, exits with status 2 when its end of life is within --warn or past.`
+
+// eolProduct matches endoflife.date product names, which also name the
+// cache file
+var eolProduct = regexp.MustCompile(`^[a-z0-9][a-z0-9._+-]*$`)
+
+// eolCycle is one release cycle of an endoflife.date answer. EOL is a date,
+// false when none is announced, or true when reached on an unknown date.
+type eolCycle struct {
+	Cycle       json.RawMessage `json:"cycle"`
+	ReleaseDate string          `json:"releaseDate"`
+	EOL         json.RawMessage `json:"eol"`
+	Latest      string          `json:"latest"`
+}
+
+// name returns the cycle name, which the API sends as a string or a number
+func (c eolCycle) name() string {
+	var s string
+	if json.Unmarshal(c.Cycle, &s) == nil {
+		return s
+	}
+	return string(c.Cycle)
+}
+
+// end returns the end of life date, or whether it has passed when the
+// answer has no date
+func (c eolCycle) end() (time.Time, bool, error) {
+	var reached bool
+	if json.Unmarshal(c.EOL, &reached) == nil {
+		return time.Time{}, reached, nil
+	}
+	var date string
+	if err := json.Unmarshal(c.EOL, &date); err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid eol value %s", c.EOL)
+	}
+	t, err := time.ParseInLocation("2006-01-02", date, time.UTC)
+	return t, err == nil && !t.After(clock.Now()), err
+}
+
+// eolCachePath returns the cache file of a product
+func eolCachePath(product string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "timeago", "eol", product+".json"), nil
+}
+
+// fetchEOL downloads the cycles of a product and refreshes the cache
+func fetchEOL(product, path string, timeout time.Duration) ([]eolCycle, error) {
+	req, err := http.NewRequest("GET", "https://endoflife.date/api/"+product+".json", nil)
+	if err != nil {
+		return nil, err
+	}
+	var cycles []eolCycle
+	if err := getJSON(&http.Client{Timeout: timeout}, req, &cycles); err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(cycles); err == nil && os.MkdirAll(filepath.Dir(path), 0o755) == nil {
+		// A failed cache write only costs a download next time
+		os.WriteFile(path, data, 0o644)
+	}
+	return cycles, nil
+}
+
+// loadEOL returns the cycles of a product from a fresh cache or the API,
+// falling back to a stale cache when the API is unreachable
+func loadEOL(product string, maxAge, timeout time.Duration, offline bool) ([]eolCycle, error) {
+	path, err := eolCachePath(product)
+	if err != nil {
+		return nil, err
+	}
+	var cached []eolCycle
+	var age time.Duration
+	info, statErr := os.Stat(path)
+	if statErr == nil {
+		data, err := os.ReadFile(path)
+		if err == nil && json.Unmarshal(data, &cached) == nil {
+			age = time.Since(info.ModTime())
+		} else {
+			cached = nil
+		}
+	}
+
+	if cached != nil && (offline || age <= maxAge) {
+		return cached, nil
+	}
+	if offline {
+		return nil, fmt.Errorf("no cached answer for %s (run once without --offline)", product)
+	}
+	cycles, err := fetchEOL(product, path, timeout)
+	if err == nil {
+		return cycles, nil
+	}
+	if cached != nil {
+		fmt.Fprintf(os.Stderr, "Warning: endoflife.date unreachable (%s), using the cache from %s\n", err, newFormatter(1).Relative(info.ModTime()))
+		return cached, nil
+	}
+	return nil, fmt.Errorf("cannot look %s up on endoflife.date: %s", product, err)
+}
+
+// eolStatus describes the end of life of a cycle for a terminal
+func eolStatus(c eolCycle, precision int) string {
+	end, reached, err := c.end()
+	switch {
+	case err != nil:
+		return err.Error()
+	case end.IsZero() && reached:
+		return "ended (date unknown)"
+	case end.IsZero():
+		return "no end of life announced"
+	}
+	return fmt.Sprintf("%s (%s)", end.Format("2006-01-02"), timeAgo(end.UnixMilli(), precision))
+}
+
+// runEOLCommand reports the end of life of a product's release cycles
+func runEOLCommand(args []string) error {
+	fs := newFlagSet("eol")
+	out := addOutputFlags(fs, 2)
+	warn := 90 * 24 * time.Hour
+	fs.Var(durationValue{&warn}, "warn", "with a CYCLE, exit with status 2 when its end of life is this close")
+	maxAge := 24 * time.Hour
+	fs.Var(durationValue{&maxAge}, "max-age", "refresh cached answers older than this")
+	timeout := 10 * time.Second
+	fs.Var(durationValue{&timeout}, "timeout", "timeout of the lookup")
+	offline := fs.Bool("offline", false, "only use the cache")
+	positional, err := parseArgs("eol", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) == 0 || len(positional) > 2 {
+		return errors.New("eol requires a product and optionally a cycle (e.g. timeago eol ubuntu 22.04)")
+	}
+	product := strings.ToLower(positional[0])
+	if !eolProduct.MatchString(product) {
+		return fmt.Errorf("invalid product name %q", positional[0])
+	}
+
+	cycles, err := loadEOL(product, maxAge, timeout, *offline)
+	if err != nil {
+		return err
+	}
+	tty := isTTY()
+
+	if len(positional) == 1 {
+		width := len("CYCLE")
+		for _, c := range cycles {
+			width = max(width, len(c.name()))
+		}
+		if tty {
+			fmt.Printf("%-*s  %-10s  %s\n", width, "CYCLE", "RELEASED", "END OF LIFE")
+		}
+		for _, c := range cycles {
+			if tty {
+				fmt.Printf("%-*s  %-10s  %s\n", width, c.name(), c.ReleaseDate, eolStatus(c, out.precision))
+				continue
+			}
+			end, _, _ := c.end()
+			eol := "-"
+			if !end.IsZero() {
+				eol = strconv.FormatInt(emitEpoch(end.UnixMilli()), 10)
+			}
+			fmt.Printf("%s\t%s\n", c.name(), eol)
+		}
+		return nil
+	}
+
+	for _, c := range cycles {
+		if c.name() != positional[1] {
+			continue
+		}
+		end, reached, err := c.end()
+		if err != nil {
+			return err
+		}
+		if tty {
+			fmt.Printf("Product: %s %s\n", product, c.name())
+			if c.Latest != "" {
+				fmt.Printf("Latest: %s\n", c.Latest)
+			}
+			fmt.Printf("Released: %s\n", c.ReleaseDate)
+			fmt.Printf("End of life: %s\n", eolStatus(c, out.precision))
+		} else if !end.IsZero() {
+			fmt.Println(emitEpoch(end.UnixMilli()))
+		}
+		if reached || (!end.IsZero() && end.Sub(clock.Now()) <= warn) {
+			return exitStatus(2)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s has no cycle %q (run \"timeago eol %s\" for the list)", product, positional[1], product)
+}
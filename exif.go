@@ -0,0 +1,303 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// EXIF tags read by the exif subcommand
+const (
+	tagDateTime           = 0x0132
+	tagExifIFD            = 0x8769
+	tagGPSIFD             = 0x8825
+	tagDateTimeOriginal   = 0x9003
+	tagOffsetTimeOriginal = 0x9011
+	tagSubSecTimeOriginal = 0x9291
+	tagGPSTimeStamp       = 0x0007
+	tagGPSDateStamp       = 0x001d
+)
+
+// tiffEntry is a raw IFD entry
+type tiffEntry struct {
+	typ   uint16
+	count uint32
+	value []byte // the 4-byte value/offset field
+}
+
+// tiffReader decodes IFDs from a TIFF block (the EXIF payload)
+type tiffReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+// readIFD returns the entries of the IFD at offset
+func (t tiffReader) readIFD(offset uint32) (map[uint16]tiffEntry, error) {
+	if int(offset)+2 > len(t.data) {
+		return nil, errors.New("IFD offset out of range")
+	}
+	n := int(t.order.Uint16(t.data[offset:]))
+	start := int(offset) + 2
+	if start+n*12 > len(t.data) {
+		return nil, errors.New("IFD truncated")
+	}
+
+	entries := make(map[uint16]tiffEntry, n)
+	for i := 0; i < n; i++ {
+		e := t.data[start+i*12 : start+i*12+12]
+		entries[t.order.Uint16(e)] = tiffEntry{
+			typ:   t.order.Uint16(e[2:]),
+			count: t.order.Uint32(e[4:]),
+			value: e[8:12],
+		}
+	}
+	return entries, nil
+}
+
+// bytesOf returns the payload of an entry whose values take size bytes each
+func (t tiffReader) bytesOf(e tiffEntry, size int) ([]byte, bool) {
+	total := int(e.count) * size
+	if total <= 4 {
+		return e.value[:total], true
+	}
+	offset := int(t.order.Uint32(e.value))
+	if offset < 0 || offset+total > len(t.data) || total < 0 {
+		return nil, false
+	}
+	return t.data[offset : offset+total], true
+}
+
+// ascii returns the string value of an ASCII entry
+func (t tiffReader) ascii(entries map[uint16]tiffEntry, tag uint16) string {
+	e, ok := entries[tag]
+	if !ok || e.typ != 2 {
+		return ""
+	}
+	b, ok := t.bytesOf(e, 1)
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimRight(string(b), "\x00"))
+}
+
+// rationals returns the values of a RATIONAL entry
+func (t tiffReader) rationals(entries map[uint16]tiffEntry, tag uint16) []float64 {
+	e, ok := entries[tag]
+	if !ok || e.typ != 5 {
+		return nil
+	}
+	b, ok := t.bytesOf(e, 8)
+	if !ok {
+		return nil
+	}
+	values := make([]float64, e.count)
+	for i := range values {
+		num := t.order.Uint32(b[i*8:])
+		den := t.order.Uint32(b[i*8+4:])
+		if den != 0 {
+			values[i] = float64(num) / float64(den)
+		}
+	}
+	return values
+}
+
+// pointer returns the IFD offset stored in a LONG entry
+func (t tiffReader) pointer(entries map[uint16]tiffEntry, tag uint16) (uint32, bool) {
+	e, ok := entries[tag]
+	if !ok || (e.typ != 4 && e.typ != 13) {
+		return 0, false
+	}
+	return t.order.Uint32(e.value), true
+}
+
+// exifTimes holds the capture times found in an image
+type exifTimes struct {
+	original time.Time // DateTimeOriginal, or DateTime as a fallback
+	hasZone  bool      // original carried an OffsetTimeOriginal
+	gps      time.Time // GPS date and time (always UTC)
+}
+
+// readExifPayload returns the TIFF block of a JPEG's APP1 Exif segment
+func readExifPayload(r io.Reader) ([]byte, error) {
+	br := bufio.NewReader(r)
+	var soi [2]byte
+	if _, err := io.ReadFull(br, soi[:]); err != nil || soi != [2]byte{0xFF, 0xD8} {
+		return nil, errors.New("not a JPEG file")
+	}
+
+	for {
+		var marker [4]byte
+		if _, err := io.ReadFull(br, marker[:]); err != nil {
+			return nil, errors.New("no EXIF metadata found")
+		}
+		if marker[0] != 0xFF || marker[1] == 0xDA {
+			// Start of scan: metadata segments are over
+			return nil, errors.New("no EXIF metadata found")
+		}
+		length := int(binary.BigEndian.Uint16(marker[2:])) - 2
+		if length < 0 {
+			return nil, errors.New("corrupt JPEG segment")
+		}
+		segment := make([]byte, length)
+		if _, err := io.ReadFull(br, segment); err != nil {
+			return nil, errors.New("corrupt JPEG segment")
+		}
+		if marker[1] == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:], nil
+		}
+	}
+}
+
+// parseExifTimes extracts the capture times of a TIFF/EXIF block
+func parseExifTimes(data []byte) (exifTimes, error) {
+	var times exifTimes
+	if len(data) < 8 {
+		return times, errors.New("EXIF block too short")
+	}
+
+	t := tiffReader{data: data}
+	switch string(data[:2]) {
+	case "II":
+		t.order = binary.LittleEndian
+	case "MM":
+		t.order = binary.BigEndian
+	default:
+		return times, errors.New("invalid EXIF byte order")
+	}
+
+	ifd0, err := t.readIFD(t.order.Uint32(data[4:]))
+	if err != nil {
+		return times, err
+	}
+
+	value := t.ascii(ifd0, tagDateTime)
+	offset, subsec := "", ""
+	if ptr, ok := t.pointer(ifd0, tagExifIFD); ok {
+		if exif, err := t.readIFD(ptr); err == nil {
+			if original := t.ascii(exif, tagDateTimeOriginal); original != "" {
+				value = original
+			}
+			offset = t.ascii(exif, tagOffsetTimeOriginal)
+			subsec = t.ascii(exif, tagSubSecTimeOriginal)
+		}
+	}
+
+	if value != "" {
+		if subsec != "" {
+			value += "." + subsec
+		}
+		if offset != "" {
+			if parsed, err := time.Parse("2006:01:02 15:04:05-07:00", value+offset); err == nil {
+				times.original, times.hasZone = parsed, true
+			}
+		}
+		if !times.hasZone {
+			// Without an offset the camera clock is assumed to be local time
+			times.original, _ = time.ParseInLocation("2006:01:02 15:04:05", value, time.Local)
+		}
+	}
+
+	if ptr, ok := t.pointer(ifd0, tagGPSIFD); ok {
+		if gps, err := t.readIFD(ptr); err == nil {
+			date := t.ascii(gps, tagGPSDateStamp)
+			hms := t.rationals(gps, tagGPSTimeStamp)
+			if day, err := time.Parse("2006:01:02", date); err == nil && len(hms) == 3 {
+				seconds := hms[0]*3600 + hms[1]*60 + hms[2]
+				times.gps = day.Add(time.Duration(seconds * float64(time.Second)))
+			}
+		}
+	}
+
+	if times.original.IsZero() && times.gps.IsZero() {
+		return times, errors.New("no capture time in EXIF metadata")
+	}
+	return times, nil
+}
+
+// runExifCommand reports the EXIF capture time of an image absolutely and
+// relatively, with its delta to the file mtime. Piped output is the capture
+// epoch in milliseconds.
+func runExifCommand(args []string) error {
+	fs := newFlagSet("exif")
+	out := addOutputFlags(fs, 1)
+	positional, err := parseArgs("exif", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return fmt.Errorf("exif requires an image file")
+	}
+	path := positional[0]
+	precision := out.precision
+	isTTY := isTTY()
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	payload, err := readExifPayload(f)
+	if err != nil {
+		return fmt.Errorf("%s: %s", path, err)
+	}
+	times, err := parseExifTimes(payload)
+	if err != nil {
+		return fmt.Errorf("%s: %s", path, err)
+	}
+
+	captured := times.original
+	if captured.IsZero() {
+		captured = times.gps
+	}
+	if !isTTY {
+		fmt.Println(emitEpoch(captured.UnixMilli()))
+		return nil
+	}
+
+	durations := newFormatter(precision)
+	if !times.original.IsZero() {
+		zone := ""
+		if !times.hasZone {
+			zone = " (no offset recorded, assuming local time)"
+		}
+		fmt.Printf("Captured: %d%s\n", times.original.UnixMilli(), zone)
+		fmt.Printf("UTC: %s\n", formatDateTime(times.original, true))
+		fmt.Printf("Local: %s\n", formatDateTime(times.original.Local(), false))
+		fmt.Printf("Time ago: %s\n", timeAgo(times.original.UnixMilli(), precision))
+	}
+	if !times.gps.IsZero() {
+		fmt.Printf("GPS Time: %d (%s UTC, %s)\n", times.gps.UnixMilli(), formatDateTime(times.gps, true), timeAgo(times.gps.UnixMilli(), precision))
+		if !times.original.IsZero() {
+			fmt.Printf("Camera Clock Offset: %s\n", signedDuration(durations, times.original.Sub(times.gps)))
+		}
+	}
+
+	mtime := info.ModTime()
+	fmt.Printf("File Modified: %s (%s)\n", formatDateTime(mtime, false), timeAgo(mtime.UnixMilli(), precision))
+	fmt.Printf("Modified After Capture: %s\n", signedDuration(durations, mtime.Sub(captured)))
+	return nil
+}
+
+// signedDuration renders d with a sign, e.g. "+2 hours" or "-5 minutes"
+func signedDuration(f *timeago.Formatter, d time.Duration) string {
+	if d < 0 {
+		return "-" + f.Duration(d)
+	}
+	return "+" + f.Duration(d)
+}
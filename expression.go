@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// expressionOperator matches the + and - of an expression; the surrounding
+// spaces keep ISO dates and zone offsets ("+01:00") in one piece
+var expressionOperator = regexp.MustCompile(`\s+([+-])\s+`)
+
+// parseExpression evaluates "BASE + DURATION - DURATION..." such as
+// "now + 2h - 30min" or "1700000000000 + 1 day", left to right with
+// calendar units. ok is false when input has no operator.
+func parseExpression(input string) (epochMs int64, ok bool, err error) {
+	operators := expressionOperator.FindAllStringSubmatchIndex(input, -1)
+	if len(operators) == 0 {
+		return 0, false, nil
+	}
+
+	base := input[:operators[0][0]]
+	epochMs, err = parseEpoch(base)
+	if err != nil {
+		return 0, true, fmt.Errorf("invalid timestamp %q in expression", base)
+	}
+	t := time.UnixMilli(epochMs)
+	for i, op := range operators {
+		end := len(input)
+		if i+1 < len(operators) {
+			end = operators[i+1][0]
+		}
+		term := input[op[1]:end]
+		d, err := timeago.ParseCalendarDuration(translateInput(term))
+		if err != nil {
+			return 0, true, fmt.Errorf("invalid duration %q in expression", term)
+		}
+		if input[op[2]:op[3]] == "+" {
+			t = d.Add(t)
+		} else {
+			t = d.Sub(t)
+		}
+	}
+	return t.UnixMilli(), true, nil
+}
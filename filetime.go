@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// fileDescription details the file command for its usage
+const fileDescription = `Without --stat, every timestamp the system records is listed: mtime
+(modified), atime (accessed), ctime (changed, i.e. metadata) and birth
+(created, where the file system keeps it). Piped output is the --stat epoch,
+mtime by default.`
+
+// fileStats are the --stat names with their labels, in display order
+var fileStats = []struct{ name, label string }{
+	{"mtime", "Modified"}, {"atime", "Accessed"}, {"ctime", "Changed"}, {"birth", "Created"},
+}
+
+// runFileCommand prints the timestamps of a file
+func runFileCommand(args []string) error {
+	fs := newFlagSet("file")
+	out := addOutputFlags(fs, 2)
+	stat := fs.String("stat", "", "timestamp to print: mtime, atime, ctime or birth (default: all, or mtime when piped)")
+	positional, err := parseArgs("file", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("file requires one path")
+	}
+	path := positional[0]
+
+	names := make([]string, 0, len(fileStats))
+	for _, s := range fileStats {
+		names = append(names, s.name)
+	}
+	selected := *stat
+	if selected == "" && !isTTY() {
+		selected = "mtime"
+	}
+	if selected != "" && !slices.Contains(names, selected) {
+		return fmt.Errorf("unsupported --stat %q (supported: %s)", selected, strings.Join(names, ", "))
+	}
+
+	times, err := fileTimes(path)
+	if err != nil {
+		return err
+	}
+	if selected != "" {
+		t, ok := times[selected]
+		if !ok {
+			return fmt.Errorf("%s: %s is not recorded on this system or file system", path, selected)
+		}
+		if !isTTY() {
+			fmt.Println(emitEpoch(t.UnixMilli()))
+			return nil
+		}
+	}
+
+	fmt.Printf("File: %s\n", path)
+	for _, s := range fileStats {
+		t, ok := times[s.name]
+		if !ok || (selected != "" && s.name != selected) {
+			continue
+		}
+		fmt.Printf("%s: %s (%s)\n", s.label, formatDateTime(t, false), timeAgo(t.UnixMilli(), out.precision))
+	}
+	return nil
+}
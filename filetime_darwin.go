@@ -0,0 +1,22 @@
+package main
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// fileTimes returns the timestamps of path by --stat name
+func fileTimes(path string) (map[string]time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	times := map[string]time.Time{"mtime": info.ModTime()}
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		times["atime"] = time.Unix(st.Atimespec.Unix())
+		times["ctime"] = time.Unix(st.Ctimespec.Unix())
+		times["birth"] = time.Unix(st.Birthtimespec.Unix())
+	}
+	return times, nil
+}
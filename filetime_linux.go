@@ -0,0 +1,28 @@
+package main
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// fileTimes returns the timestamps of path by --stat name; birth needs
+// statx and a file system that records it
+func fileTimes(path string) (map[string]time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	times := map[string]time.Time{"mtime": info.ModTime()}
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		times["atime"] = time.Unix(st.Atim.Unix())
+		times["ctime"] = time.Unix(st.Ctim.Unix())
+	}
+	var stx unix.Statx_t
+	if unix.Statx(unix.AT_FDCWD, path, 0, unix.STATX_BTIME, &stx) == nil && stx.Mask&unix.STATX_BTIME != 0 {
+		times["birth"] = time.Unix(stx.Btime.Sec, int64(stx.Btime.Nsec))
+	}
+	return times, nil
+}
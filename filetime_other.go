@@ -0,0 +1,18 @@
+//go:build !linux && !darwin
+
+package main
+
+import (
+	"os"
+	"time"
+)
+
+// fileTimes returns the timestamps of path by --stat name; only the
+// modification time is portable
+func fileTimes(path string) (map[string]time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]time.Time{"mtime": info.ModTime()}, nil
+}
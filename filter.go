@@ -0,0 +1,463 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ANSI color codes used to highlight relative times by age
+const (
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorRed    = "\033[31m"
+	colorReset  = "\033[0m"
+)
+
+// timestampDetector finds one family of timestamps embedded in free text.
+// format renders a time back in the same shape as the original match.
+type timestampDetector struct {
+	name   string
+	re     *regexp.Regexp
+	parse  func(string) (time.Time, error)
+	format func(t time.Time, original string) string
+}
+
+// withRecentYear places a time parsed without a year (year 0) in the most
+// recent year where it is not more than a day ahead
+func withRecentYear(t time.Time) time.Time {
+	now := clock.Now()
+	t = t.AddDate(now.Year(), 0, 0)
+	if t.After(now.Add(24 * time.Hour)) {
+		t = t.AddDate(-1, 0, 0)
+	}
+	return t
+}
+
+// isoLayout builds the Go layout matching an ISO 8601 timestamp as written,
+// keeping its separator, fractional digits and offset style
+func isoLayout(s string) string {
+	layout := "2006-01-02T15:04:05"
+	if s[10] == ' ' {
+		layout = "2006-01-02 15:04:05"
+	}
+
+	rest := s[19:]
+	if len(rest) > 0 && (rest[0] == '.' || rest[0] == ',') {
+		digits := 1
+		for digits < len(rest) && rest[digits] >= '0' && rest[digits] <= '9' {
+			digits++
+		}
+		layout += string(rest[0]) + strings.Repeat("0", digits-1)
+		rest = rest[digits:]
+	}
+
+	switch {
+	case rest == "Z":
+		layout += "Z07:00"
+	case strings.Contains(rest, ":"):
+		layout += "-07:00"
+	case rest != "":
+		layout += "-0700"
+	}
+	return layout
+}
+
+// defaultDetectors covers the timestamp formats most commonly found in logs
+var defaultDetectors = []timestampDetector{
+	{
+		name: "iso8601",
+		re:   regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?`),
+		parse: func(s string) (time.Time, error) {
+			return time.ParseInLocation(isoLayout(s), s, time.Local)
+		},
+		format: func(t time.Time, original string) string {
+			return t.Format(isoLayout(original))
+		},
+	},
+	{
+		name: "syslog",
+		re:   regexp.MustCompile(`\b(?:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec) [ \d]\d \d{2}:\d{2}:\d{2}\b`),
+		parse: func(s string) (time.Time, error) {
+			t, err := time.ParseInLocation(time.Stamp, s, time.Local)
+			if err != nil {
+				return t, err
+			}
+			// Syslog omits the year
+			return withRecentYear(t), nil
+		},
+		format: func(t time.Time, original string) string {
+			return t.Format(time.Stamp)
+		},
+	},
+	{
+		name: "epoch-ms",
+		re:   regexp.MustCompile(`\b\d{13}\b`),
+		parse: func(s string) (time.Time, error) {
+			ms, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return time.UnixMilli(ms), nil
+		},
+		format: func(t time.Time, original string) string {
+			return strconv.FormatInt(t.UnixMilli(), 10)
+		},
+	},
+}
+
+// filterOptions controls how the stream filter rewrites and selects lines
+type filterOptions struct {
+	precision    int
+	detectors    []timestampDetector
+	since        int64 // lower bound in epoch ms, -1 when unset
+	until        int64 // upper bound in epoch ms, -1 when unset
+	annotate     bool  // keep the original timestamp and append the relative time
+	location     *time.Location
+	layout       string // render absolute times with this layout instead of relative ones
+	maxLineBytes int    // longer lines are passed through untouched
+	color        bool
+	freshAge     int64 // ages below this (ms) are green
+	staleAge     int64 // ages below this (ms) are yellow, older are red
+}
+
+// filterDescription details the filter command in its usage
+const filterDescription = `Replaces detected timestamps (ISO 8601, syslog, epoch ms, config detectors)
+with relative times. --since/--until keep only lines within a window
+relative to now; lines without a timestamp follow the previous one.
+--out formats: datetime, rfc3339, rfc3339nano, rfc1123, rfc1123z, rfc822,
+kitchen, stamp or a Go layout.`
+
+// runFilterCommand reads the filter flags and filters stdin to stdout
+func runFilterCommand(args []string) error {
+	return runFilterWith("filter", nil, args)
+}
+
+// runFilterWith implements the filter command and its variants, which try
+// extra detectors before the configured and default ones
+func runFilterWith(name string, extra []timestampDetector, args []string) error {
+	var since, until time.Duration
+	var location *time.Location
+	fs := newFlagSet(name)
+	out := addOutputFlags(fs, 1)
+	fs.Var(durationValue{&since}, "since", "drop lines older than this long ago (e.g. 2h)")
+	fs.Var(durationValue{&until}, "until", "drop lines newer than this long ago (e.g. 30m)")
+	annotate := fs.Bool("annotate", false, "keep timestamps and append the relative time")
+	color := fs.String("color", "auto", "color relative times by age: auto, always or never")
+	thresholds := fs.String("color-thresholds", "5m,1h", "fresh and stale ages for coloring")
+	fs.Var(locationValue{&location}, "tz", "render timestamps as absolute times in this zone")
+	layout := fs.String("out", "", "render timestamps as absolute times in this format")
+	maxLineBytes := fs.Int("max-line-bytes", 1024*1024, "pass longer lines through untouched")
+	progress := fs.Bool("progress", false, "report the bytes processed on stderr")
+	if _, err := parseArgs(name, fs, args); err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+
+	detectors, err := loadDetectors()
+	if err != nil {
+		return err
+	}
+	detectors = append(extra, detectors...)
+
+	opts := filterOptions{
+		precision:    out.precision,
+		detectors:    detectors,
+		since:        -1,
+		until:        -1,
+		annotate:     *annotate,
+		location:     location,
+		maxLineBytes: *maxLineBytes,
+	}
+
+	now := clock.Now().UnixMilli()
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "since":
+			opts.since = now - since.Milliseconds()
+		case "until":
+			opts.until = now - until.Milliseconds()
+		}
+	})
+
+	switch *color {
+	case "always":
+		opts.color = true
+	case "never":
+		opts.color = false
+	case "auto":
+		opts.color = isTTY() && os.Getenv("NO_COLOR") == ""
+	default:
+		return fmt.Errorf("--color must be auto, always or never")
+	}
+
+	bounds := strings.Split(*thresholds, ",")
+	if len(bounds) != 2 {
+		return fmt.Errorf("--color-thresholds requires two values (e.g. \"5m,1h\")")
+	}
+	if opts.freshAge, err = parseTimeString(bounds[0]); err != nil {
+		return errors.New(describeParseError(err))
+	}
+	if opts.staleAge, err = parseTimeString(bounds[1]); err != nil {
+		return errors.New(describeParseError(err))
+	}
+	if opts.freshAge > opts.staleAge {
+		return fmt.Errorf("--color-thresholds must be in increasing order")
+	}
+
+	if *maxLineBytes < 1 {
+		return fmt.Errorf("--max-line-bytes requires a positive number")
+	}
+
+	if *layout != "" {
+		var ok bool
+		if opts.layout, ok = outputLayouts[strings.ToLower(*layout)]; !ok {
+			// Anything else is taken as a Go reference layout
+			opts.layout = *layout
+		}
+	}
+	// Re-rendering into a zone without an explicit format uses the default one
+	if opts.location != nil && opts.layout == "" {
+		opts.layout = outputLayouts["datetime"]
+	}
+	if opts.layout != "" && opts.location == nil {
+		opts.location = time.Local
+	}
+
+	var input io.Reader = os.Stdin
+	if *progress {
+		var stop func()
+		input, stop = withProgress(input)
+		defer stop()
+	}
+	return runFilter(input, os.Stdout, opts)
+}
+
+// outputLayouts names the formats accepted by --out
+var outputLayouts = map[string]string{
+	"datetime":    "2006-01-02 15:04:05",
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"rfc1123":     time.RFC1123,
+	"rfc1123z":    time.RFC1123Z,
+	"rfc822":      time.RFC822,
+	"kitchen":     time.Kitchen,
+	"stamp":       time.Stamp,
+}
+
+// ageColor picks the highlight color for a timestamp based on its age
+func (o filterOptions) ageColor(epochMs int64) string {
+	age := clock.Now().UnixMilli() - epochMs
+	switch {
+	case age < o.freshAge:
+		return colorGreen
+	case age < o.staleAge:
+		return colorYellow
+	default:
+		return colorRed
+	}
+}
+
+// timestampMatch is a detected timestamp located in a line
+type timestampMatch struct {
+	start, end int
+	t          time.Time
+	detector   *timestampDetector
+}
+
+// findTimestamps returns the non-overlapping timestamps of a line in order
+func findTimestamps(line string, detectors []timestampDetector) []timestampMatch {
+	var matches []timestampMatch
+	for i := range detectors {
+		d := &detectors[i]
+		for _, loc := range d.re.FindAllStringSubmatchIndex(line, -1) {
+			// Patterns with a capture group only rewrite the group
+			if len(loc) >= 4 && loc[2] >= 0 {
+				loc = loc[2:4]
+			}
+			t, err := d.parse(line[loc[0]:loc[1]])
+			if err != nil {
+				continue
+			}
+			matches = append(matches, timestampMatch{start: loc[0], end: loc[1], t: t, detector: d})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].start < matches[j].start
+	})
+
+	// Earlier detectors win when matches overlap
+	var result []timestampMatch
+	lastEnd := -1
+	for _, m := range matches {
+		if m.start < lastEnd {
+			continue
+		}
+		result = append(result, m)
+		lastEnd = m.end
+	}
+	return result
+}
+
+// inWindow reports whether an epoch falls within the --since/--until bounds
+func (o filterOptions) inWindow(epochMs int64) bool {
+	if o.since >= 0 && epochMs < o.since {
+		return false
+	}
+	if o.until >= 0 && epochMs > o.until {
+		return false
+	}
+	return true
+}
+
+// windowed reports whether --since or --until was requested
+func (o filterOptions) windowed() bool {
+	return o.since >= 0 || o.until >= 0
+}
+
+// rewriteLine replaces every detected timestamp with its relative time (or
+// its absolute time in the --tz/--out zone and format), or appends it in
+// parentheses after the timestamp in annotate mode
+func rewriteLine(line string, matches []timestampMatch, opts filterOptions) string {
+	var out []byte
+	last := 0
+	for _, m := range matches {
+		if opts.annotate {
+			out = append(out, line[last:m.end]...)
+			out = append(out, " ("...)
+		} else {
+			out = append(out, line[last:m.start]...)
+		}
+		var relative string
+		if opts.layout != "" {
+			relative = m.t.In(opts.location).Format(opts.layout)
+		} else {
+			relative = timeAgo(m.t.UnixMilli(), opts.precision)
+		}
+		if opts.color {
+			relative = opts.ageColor(m.t.UnixMilli()) + relative + colorReset
+		}
+		out = append(out, relative...)
+		if opts.annotate {
+			out = append(out, ')')
+		}
+		last = m.end
+	}
+	out = append(out, line[last:]...)
+	return string(out)
+}
+
+// isBinary reports whether a line holds data that must not be rewritten
+func isBinary(line []byte) bool {
+	return bytes.IndexByte(line, 0) >= 0 || !utf8.Valid(line)
+}
+
+// splitEOL separates a line from its "\n" or "\r\n" terminator
+func splitEOL(line []byte) ([]byte, []byte) {
+	n := len(line)
+	if n > 0 && line[n-1] == '\n' {
+		n--
+		if n > 0 && line[n-1] == '\r' {
+			n--
+		}
+	}
+	return line[:n], line[n:]
+}
+
+// scanLines feeds every line of r, terminator included, to process, which
+// writes to out. The rest of a line longer than maxLineBytes is fed to pass
+// chunk by chunk instead of being buffered, so memory stays bounded whatever
+// the size of the input. Output is flushed whenever the input stalls.
+func scanLines(r io.Reader, w io.Writer, maxLineBytes int, process, pass func(out *bufio.Writer, b []byte) error) error {
+	reader := bufio.NewReaderSize(r, 64*1024)
+	out := bufio.NewWriter(w)
+
+	var line []byte
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		line = append(line, chunk...)
+		if err == bufio.ErrBufferFull {
+			if len(line) <= maxLineBytes {
+				continue
+			}
+			// Oversized line: stream the rest without buffering it
+			if perr := pass(out, line); perr != nil {
+				return perr
+			}
+			line = line[:0]
+			for err == bufio.ErrBufferFull {
+				chunk, err = reader.ReadSlice('\n')
+				if perr := pass(out, chunk); perr != nil {
+					return perr
+				}
+			}
+		} else if len(line) > 0 {
+			if perr := process(out, line); perr != nil {
+				return perr
+			}
+			line = line[:0]
+		}
+
+		if err == io.EOF {
+			return out.Flush()
+		}
+		if err != nil {
+			return err
+		}
+		// Keep interactive pipes (tail -f) responsive
+		if reader.Buffered() == 0 {
+			if err := out.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// runFilter copies r to w, humanizing timestamps and applying the time window.
+// Lines without a timestamp (stack traces, wrapped messages) follow the
+// decision made for the previous timestamped line. Binary lines and lines
+// longer than maxLineBytes are passed through byte for byte.
+func runFilter(r io.Reader, w io.Writer, opts filterOptions) error {
+	keep := !opts.windowed()
+	pass := func(out *bufio.Writer, b []byte) error {
+		if !keep {
+			return nil
+		}
+		_, err := out.Write(b)
+		return err
+	}
+	process := func(out *bufio.Writer, line []byte) error {
+		body, eol := splitEOL(line)
+		if len(body) > opts.maxLineBytes || isBinary(body) {
+			return pass(out, line)
+		}
+
+		text := string(body)
+		matches := findTimestamps(text, opts.detectors)
+		if len(matches) > 0 && opts.windowed() {
+			keep = opts.inWindow(matches[0].t.UnixMilli())
+		}
+		if !keep {
+			return nil
+		}
+		if _, err := out.WriteString(rewriteLine(text, matches, opts)); err != nil {
+			return err
+		}
+		_, err := out.Write(eol)
+		return err
+	}
+	return scanLines(r, w, opts.maxLineBytes, process, pass)
+}
@@ -4,6 +4,7 @@ go 1.24.0
 
 toolchain go1.24.10
 
-require golang.org/x/term v0.37.0
-
-require golang.org/x/sys v0.38.0 // indirect
+require (
+	golang.org/x/sys v0.38.0
+	golang.org/x/term v0.37.0
+)
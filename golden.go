@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// goldenDescription details the golden command for its usage
+const goldenDescription = `Renders a fixed battery of offsets around --now with the selected style,
+language and precision, one "offset<TAB>epoch<TAB>relative" line each, so
+formatting changes between versions show up as a diff of two snapshots.
+--now defaults to 2023-11-14T22:13:20Z here instead of the current time.`
+
+// goldenNow is the default reference instant, 2023-11-14T22:13:20Z
+const goldenNow = 1700000000000
+
+// goldenOffsets are the battery of the golden command: the edges of every
+// unit and of the just-now, git and kubectl thresholds, past and future
+var goldenOffsets = []time.Duration{
+	0,
+	500 * time.Millisecond,
+	time.Second,
+	45 * time.Second,
+	89 * time.Second,
+	90 * time.Second,
+	119 * time.Second,
+	2 * time.Minute,
+	5*time.Minute + 30*time.Second,
+	59*time.Minute + 59*time.Second,
+	time.Hour,
+	2*time.Hour + 30*time.Minute,
+	7*time.Hour + 59*time.Minute,
+	23 * time.Hour,
+	24 * time.Hour,
+	36 * time.Hour,
+	47 * time.Hour,
+	6*24*time.Hour + 23*time.Hour,
+	7 * 24 * time.Hour,
+	13 * 24 * time.Hour,
+	29 * 24 * time.Hour,
+	30 * 24 * time.Hour,
+	45 * 24 * time.Hour,
+	364 * 24 * time.Hour,
+	365 * 24 * time.Hour,
+	400 * 24 * time.Hour,
+	2*365*24*time.Hour + 120*24*time.Hour,
+	10 * 365 * 24 * time.Hour,
+}
+
+// runGoldenCommand prints the golden battery
+func runGoldenCommand(args []string) error {
+	fs := newFlagSet("golden")
+	out := addOutputFlags(fs, 1)
+	positional, err := parseArgs("golden", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return errors.New("golden takes no arguments")
+	}
+	now := time.UnixMilli(goldenNow)
+	if fixed, ok := clock.(timeago.FixedClock); ok {
+		now = fixed.Now()
+	}
+	nowMs := now.UnixMilli()
+
+	fmt.Printf("# now=%d style=%s lang=%s precision=%d\n", nowMs, outputStyle, outputLang, out.precision)
+	for _, offset := range goldenOffsets {
+		// Past first, then the same offset in the future
+		deltas := []time.Duration{-offset, offset}
+		if offset == 0 {
+			deltas = deltas[:1]
+		}
+		for _, d := range deltas {
+			epochMs := now.Add(d).UnixMilli()
+			fmt.Printf("%s\t%d\t%s\n", d, epochMs, timeAgoAt(epochMs, out.precision, now))
+		}
+	}
+	return nil
+}
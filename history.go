@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// historyDescription details the history-annotate command for its usage
+const historyDescription = `Reads shell history from stdin and prefixes each command with its age:
+  history | timeago history-annotate          # bash with HISTTIMEFORMAT
+  fc -li 1 | timeago history-annotate         # zsh
+  timeago history-annotate < ~/.zsh_history   # zsh EXTENDED_HISTORY file
+  timeago history-annotate < ~/.bash_history  # "#EPOCH" lines of HISTTIMEFORMAT
+Listed times are read with --histtimeformat (default: $HISTTIMEFORMAT, or
+the formats of bash's "%F %T" and zsh's -i, -E and -f). Other lines pass
+through unchanged.`
+
+var (
+	// zshHistoryEntry is a line of a zsh EXTENDED_HISTORY file,
+	// ": START:ELAPSED;COMMAND"
+	zshHistoryEntry = regexp.MustCompile(`^: (\d+):\d+;(.*)$`)
+	// bashHistoryStamp is the "#EPOCH" line bash writes before a command
+	bashHistoryStamp = regexp.MustCompile(`^#(\d{9,11})$`)
+	// historyNumber is the event number leading a listed history line
+	historyNumber = regexp.MustCompile(`^\s*\d+\*?\s+`)
+)
+
+// historyLayouts are tried on listed history lines without
+// --histtimeformat: bash's "%F %T", then zsh's fc -li, -lE and -lf
+var historyLayouts = []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "02.01.2006 15:04", "01/02/2006 15:04"}
+
+// historyAnnotator prefixes history commands with their age
+type historyAnnotator struct {
+	layouts   []string
+	precision int
+	// stamp is the time of the last "#EPOCH" line, for the next command
+	stamp *time.Time
+}
+
+// age renders the prefix of a command run at t
+func (a *historyAnnotator) age(t time.Time) string {
+	return "[" + timeAgo(t.UnixMilli(), a.precision) + "] "
+}
+
+// annotate rewrites one line, returning false for lines to drop
+func (a *historyAnnotator) annotate(line string) (string, bool) {
+	if m := bashHistoryStamp.FindStringSubmatch(line); m != nil {
+		secs, _ := strconv.ParseInt(m[1], 10, 64)
+		t := time.Unix(secs, 0)
+		a.stamp = &t
+		return "", false
+	}
+	if a.stamp != nil {
+		t := *a.stamp
+		a.stamp = nil
+		return a.age(t) + line, true
+	}
+	if m := zshHistoryEntry.FindStringSubmatch(line); m != nil {
+		secs, _ := strconv.ParseInt(m[1], 10, 64)
+		return a.age(time.Unix(secs, 0)) + m[2], true
+	}
+
+	number := historyNumber.FindString(line)
+	if number == "" {
+		return line, true
+	}
+	rest := line[len(number):]
+	for _, layout := range a.layouts {
+		// The time ends at a space, wherever month or day names put it
+		for end := range rest {
+			if rest[end] != ' ' {
+				continue
+			}
+			if t, err := time.ParseInLocation(layout, rest[:end], time.Local); err == nil {
+				if t.Year() == 0 {
+					t = withRecentYear(t)
+				}
+				return number + a.age(t) + strings.TrimLeft(rest[end:], " "), true
+			}
+		}
+	}
+	return line, true
+}
+
+// runHistoryAnnotateCommand prefixes shell history read from stdin with
+// relative ages
+func runHistoryAnnotateCommand(args []string) error {
+	fs := newFlagSet("history-annotate")
+	out := addOutputFlags(fs, 1)
+	format := fs.String("histtimeformat", os.Getenv("HISTTIMEFORMAT"), "strftime format of listed history times")
+	positional, err := parseArgs("history-annotate", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return errors.New("history-annotate reads stdin and takes no arguments")
+	}
+
+	a := &historyAnnotator{layouts: historyLayouts, precision: out.precision}
+	if strings.TrimSpace(*format) != "" {
+		layout, err := timeago.StrftimeLayout(strings.TrimSpace(*format))
+		if err != nil {
+			return fmt.Errorf("--histtimeformat: %s", err)
+		}
+		a.layouts = []string{layout}
+	}
+	return annotateHistory(os.Stdin, os.Stdout, a)
+}
+
+// annotateHistory copies r to w through the annotator
+func annotateHistory(r io.Reader, w io.Writer, a *historyAnnotator) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	out := bufio.NewWriter(w)
+	for scanner.Scan() {
+		if line, keep := a.annotate(scanner.Text()); keep {
+			fmt.Fprintln(out, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return out.Flush()
+}
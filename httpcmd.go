@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// httpDateHeaders are the headers reported by the http command, in order
+var httpDateHeaders = []string{"Date", "Last-Modified", "Expires", "Retry-After"}
+
+// cacheLifetimes are the Cache-Control directives giving a freshness lifetime
+var cacheLifetimes = []string{"max-age", "s-maxage"}
+
+// httpDescription details the http command for its usage
+const httpDescription = `Reports Date, Last-Modified, Expires and Retry-After as relative times, and
+the expiry of Cache-Control max-age/s-maxage (counted from Date, minus Age).
+A URL is requested with HEAD (GET when HEAD is refused); other arguments are
+header values ("120", "max-age=3600"), "Name: value" lines, or "-" to read
+response headers from stdin (e.g. curl -sI URL | timeago http -).`
+
+// httpHeaderTime parses the value of a date header. Retry-After may also be
+// a number of seconds, counted from the response Date.
+func httpHeaderTime(name, value string, date time.Time) (time.Time, error) {
+	if name == "Retry-After" {
+		if secs, err := strconv.ParseInt(value, 10, 64); err == nil && secs >= 0 {
+			return date.Add(time.Duration(secs) * time.Second), nil
+		}
+	}
+	return http.ParseTime(value)
+}
+
+// cacheDirective returns the value in seconds of a Cache-Control directive
+func cacheDirective(value, directive string) (int64, bool) {
+	for _, part := range strings.Split(value, ",") {
+		name, arg, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || !strings.EqualFold(name, directive) {
+			continue
+		}
+		secs, err := strconv.ParseInt(strings.Trim(arg, `"`), 10, 64)
+		if err != nil || secs < 0 {
+			return 0, false
+		}
+		return secs, true
+	}
+	return 0, false
+}
+
+// fetchHeaders requests url with HEAD, falling back to GET for servers that
+// refuse HEAD, and returns the response status and headers
+func fetchHeaders(url string, timeout time.Duration) (string, http.Header, error) {
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Head(url)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		resp.Body.Close()
+		resp, err = client.Get(url)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+	return resp.Status, resp.Header, nil
+}
+
+// readHeaderLines reads "Name: value" lines, skipping status lines
+func readHeaderLines(r io.Reader) (http.Header, error) {
+	header := http.Header{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || strings.HasPrefix(name, "HTTP/") {
+			continue
+		}
+		header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return header, scanner.Err()
+}
+
+// urlDescription details the url command for its usage
+const urlDescription = `Requests each URL with HEAD (GET when HEAD is refused) and reports its
+Date, Last-Modified, Expires, Retry-After and Cache-Control lifetimes as
+epochs and relative times, with the skew between the server clock and this
+machine. Piped output adds a "Clock-Skew<TAB>ms" line (server minus local).`
+
+// isURL reports whether arg is fetched rather than parsed as a header
+func isURL(arg string) bool {
+	return strings.HasPrefix(arg, "http://") || strings.HasPrefix(arg, "https://")
+}
+
+// runHTTPCommand reports the date headers of a URL or of given header values
+func runHTTPCommand(args []string) error {
+	return runHTTPWith("http", args)
+}
+
+// runURLCommand reports the date headers of live URLs only
+func runURLCommand(args []string) error {
+	return runHTTPWith("url", args)
+}
+
+// runHTTPWith implements the http command and url, its variant restricted
+// to URLs
+func runHTTPWith(name string, args []string) error {
+	fs := newFlagSet(name)
+	out := addOutputFlags(fs, 2)
+	timeout := 10 * time.Second
+	fs.Var(durationValue{&timeout}, "timeout", "request timeout for URLs")
+	positional, err := parseArgs(name, fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		if name == "url" {
+			return errors.New("url requires a URL")
+		}
+		return errors.New("http requires a URL or a header value")
+	}
+	if name == "url" {
+		for _, arg := range positional {
+			if !isURL(arg) {
+				return fmt.Errorf("url requires http:// or https:// URLs, got %q (see timeago http for header values)", arg)
+			}
+		}
+	}
+
+	header := http.Header{}
+	var bare []string
+	status := ""
+	for _, arg := range positional {
+		switch {
+		case isURL(arg):
+			s, h, err := fetchHeaders(arg, timeout)
+			if err != nil {
+				return err
+			}
+			status = s
+			for name, values := range h {
+				header[name] = append(header[name], values...)
+			}
+		case arg == "-":
+			h, err := readHeaderLines(os.Stdin)
+			if err != nil {
+				return err
+			}
+			for name, values := range h {
+				header[name] = append(header[name], values...)
+			}
+		default:
+			name, value, ok := strings.Cut(arg, ":")
+			_, numErr := strconv.ParseUint(arg, 10, 63)
+			switch {
+			case ok && !strings.ContainsAny(name, " ,="):
+				header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+			case strings.Contains(arg, "="):
+				// Bare Cache-Control directives such as max-age=3600
+				header.Add("Cache-Control", arg)
+			case numErr == nil:
+				// A bare number of seconds is a Retry-After delay
+				header.Add("Retry-After", arg)
+			default:
+				bare = append(bare, strings.TrimSpace(arg))
+			}
+		}
+	}
+
+	now := clock.Now()
+	date, hasDate := now, false
+	if t, err := http.ParseTime(header.Get("Date")); err == nil {
+		date, hasDate = t, true
+	}
+
+	type report struct {
+		name, value string
+		t           time.Time
+		err         error
+	}
+	var reports []report
+	for _, name := range httpDateHeaders {
+		for _, value := range header.Values(textproto.CanonicalMIMEHeaderKey(name)) {
+			t, err := httpHeaderTime(name, value, date)
+			reports = append(reports, report{name, value, t, err})
+		}
+	}
+	// The response is already Age seconds old when Date was set by a cache
+	age, _ := strconv.ParseInt(header.Get("Age"), 10, 64)
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range cacheLifetimes {
+			if secs, ok := cacheDirective(value, directive); ok {
+				expiry := date.Add(time.Duration(secs-age) * time.Second)
+				reports = append(reports, report{"Cache-Control " + directive, strconv.FormatInt(secs, 10), expiry, nil})
+			}
+		}
+	}
+	for _, value := range bare {
+		t, err := httpHeaderTime("", value, now)
+		reports = append(reports, report{"Value", value, t, err})
+	}
+	if len(reports) == 0 {
+		return errors.New("no Date, Last-Modified, Expires, Retry-After or Cache-Control max-age found")
+	}
+
+	// Only meaningful for a live response; HTTP dates have one second resolution
+	live := hasDate && status != ""
+	skew := date.Sub(now).Round(time.Second)
+
+	if !isTTY() {
+		for _, r := range reports {
+			if r.err == nil {
+				fmt.Printf("%s\t%d\n", r.name, emitEpoch(r.t.UnixMilli()))
+			}
+		}
+		if live {
+			fmt.Printf("Clock-Skew\t%d\n", skew.Milliseconds())
+		}
+		return nil
+	}
+
+	if status != "" {
+		fmt.Printf("Status: %s\n", status)
+	}
+	for _, r := range reports {
+		switch {
+		case r.err == nil:
+			fmt.Printf("%s: %s (%d, %s, %s)\n", r.name, r.value, r.t.UnixMilli(), formatDateTime(r.t.Local(), false), timeAgo(r.t.UnixMilli(), out.precision))
+		case r.name == "Expires":
+			// Invalid values such as "0" mean already expired (RFC 9111)
+			fmt.Printf("%s: %s (invalid, treated as already expired)\n", r.name, r.value)
+		default:
+			fmt.Printf("%s: %s (invalid date)\n", r.name, r.value)
+		}
+	}
+	switch {
+	case !live:
+	case skew == 0:
+		fmt.Println("Clock skew: none (within a second)")
+	default:
+		direction := "ahead of"
+		if skew < 0 {
+			direction = "behind"
+		}
+		fmt.Printf("Clock skew: server is %s %s this machine\n", newFormatter(out.precision).Duration(skew), direction)
+	}
+	return nil
+}
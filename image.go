@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// imageDescription details the image command for its usage
+const imageDescription = `Asks the local Docker daemon first (DOCKER_HOST or /var/run/docker.sock),
+then the registry (anonymous pull token). Piped output is tab separated:
+"created" and the image epoch ms, then "layer", epoch ms and command per layer.`
+
+// imageLayer is one entry of an image's build history
+type imageLayer struct {
+	created   time.Time
+	createdBy string
+}
+
+// imageInfo is what the image command reports
+type imageInfo struct {
+	source  string
+	created time.Time
+	layers  []imageLayer
+}
+
+// imageRef is a parsed image reference
+type imageRef struct {
+	registry   string
+	repository string
+	reference  string // tag or digest
+}
+
+// parseImageRef normalizes a reference the way docker does: no registry
+// means Docker Hub, single-name repositories live under library/, and the
+// tag defaults to latest
+func parseImageRef(ref string) imageRef {
+	r := imageRef{registry: "registry-1.docker.io"}
+	name := ref
+	if i := strings.Index(name, "/"); i >= 0 {
+		host := name[:i]
+		if strings.ContainsAny(host, ".:") || host == "localhost" {
+			r.registry, name = host, name[i+1:]
+		}
+	}
+	if r.registry == "registry-1.docker.io" && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+
+	if i := strings.Index(name, "@"); i >= 0 {
+		r.repository, r.reference = name[:i], name[i+1:]
+	} else if i := strings.LastIndex(name, ":"); i >= 0 && !strings.Contains(name[i:], "/") {
+		r.repository, r.reference = name[:i], name[i+1:]
+	} else {
+		r.repository, r.reference = name, "latest"
+	}
+	return r
+}
+
+// dockerClient returns an HTTP client talking to the local daemon socket
+func dockerClient(timeout time.Duration) *http.Client {
+	socket := "/var/run/docker.sock"
+	if host := os.Getenv("DOCKER_HOST"); strings.HasPrefix(host, "unix://") {
+		socket = strings.TrimPrefix(host, "unix://")
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+}
+
+// getJSON sends req with client and decodes the JSON answer into v
+func getJSON(client *http.Client, req *http.Request, v any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s answered %s", req.URL.Host, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 16<<20)).Decode(v)
+}
+
+// daemonImage reads the image from the local Docker daemon
+func daemonImage(ref string, timeout time.Duration) (imageInfo, error) {
+	client := dockerClient(timeout)
+	name := url.PathEscape(ref)
+
+	var inspect struct {
+		Created time.Time
+	}
+	req, _ := http.NewRequest("GET", "http://docker/images/"+name+"/json", nil)
+	if err := getJSON(client, req, &inspect); err != nil {
+		return imageInfo{}, err
+	}
+
+	var history []struct {
+		Created   int64
+		CreatedBy string
+	}
+	req, _ = http.NewRequest("GET", "http://docker/images/"+name+"/history", nil)
+	if err := getJSON(client, req, &history); err != nil {
+		return imageInfo{}, err
+	}
+
+	info := imageInfo{source: "docker daemon", created: inspect.Created}
+	// The daemon lists the newest layer first
+	for i := len(history) - 1; i >= 0; i-- {
+		info.layers = append(info.layers, imageLayer{time.Unix(history[i].Created, 0), history[i].CreatedBy})
+	}
+	return info, nil
+}
+
+// registryClient performs registry requests, fetching an anonymous bearer
+// token when the registry asks for one
+type registryClient struct {
+	http  *http.Client
+	token string
+}
+
+// bearerChallenge parses the parameters of a WWW-Authenticate Bearer header
+func bearerChallenge(header string) map[string]string {
+	params := map[string]string{}
+	rest, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return params
+	}
+	for _, part := range strings.Split(rest, ",") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(part), "="); ok {
+			params[key] = strings.Trim(value, `"`)
+		}
+	}
+	return params
+}
+
+// get fetches url accepting the given media types, authenticating once
+func (c *registryClient) get(rawURL string, accept []string, v any) error {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest("GET", rawURL, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", strings.Join(accept, ", "))
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode != http.StatusUnauthorized || attempt > 0 {
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("%s answered %s", req.URL.Host, resp.Status)
+			}
+			return json.NewDecoder(io.LimitReader(resp.Body, 16<<20)).Decode(v)
+		}
+		resp.Body.Close()
+
+		challenge := bearerChallenge(resp.Header.Get("WWW-Authenticate"))
+		if challenge["realm"] == "" {
+			return fmt.Errorf("%s requires authentication", req.URL.Host)
+		}
+		query := url.Values{}
+		for _, key := range []string{"service", "scope"} {
+			if challenge[key] != "" {
+				query.Set(key, challenge[key])
+			}
+		}
+		var token struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}
+		req, _ = http.NewRequest("GET", challenge["realm"]+"?"+query.Encode(), nil)
+		if err := getJSON(c.http, req, &token); err != nil {
+			return fmt.Errorf("cannot get a pull token: %s", err)
+		}
+		c.token = token.Token
+		if c.token == "" {
+			c.token = token.AccessToken
+		}
+	}
+}
+
+// Manifest media types, single-platform and multi-platform
+var (
+	manifestTypes = []string{
+		"application/vnd.oci.image.manifest.v1+json",
+		"application/vnd.docker.distribution.manifest.v2+json",
+	}
+	indexTypes = []string{
+		"application/vnd.oci.image.index.v1+json",
+		"application/vnd.docker.distribution.manifest.list.v2+json",
+	}
+)
+
+// registryManifest covers both image manifests and indexes
+type registryManifest struct {
+	MediaType string `json:"mediaType"`
+	Config    struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+	Manifests []struct {
+		Digest   string `json:"digest"`
+		Platform struct {
+			OS           string `json:"os"`
+			Architecture string `json:"architecture"`
+			Variant      string `json:"variant"`
+		} `json:"platform"`
+	} `json:"manifests"`
+}
+
+// registryImage reads the image config from its registry, picking the
+// manifest of platform ("os/arch[/variant]") from multi-platform indexes
+func registryImage(ref, platform string, timeout time.Duration) (imageInfo, error) {
+	r := parseImageRef(ref)
+	c := &registryClient{http: &http.Client{Timeout: timeout}}
+	base := "https://" + r.registry + "/v2/" + r.repository
+
+	var manifest registryManifest
+	if err := c.get(base+"/manifests/"+r.reference, append(manifestTypes, indexTypes...), &manifest); err != nil {
+		return imageInfo{}, err
+	}
+	if len(manifest.Manifests) > 0 {
+		digest := ""
+		for _, m := range manifest.Manifests {
+			p := m.Platform.OS + "/" + m.Platform.Architecture
+			if platform == p || platform == p+"/"+m.Platform.Variant {
+				digest = m.Digest
+				break
+			}
+		}
+		if digest == "" {
+			return imageInfo{}, fmt.Errorf("%s has no %s image", ref, platform)
+		}
+		manifest = registryManifest{}
+		if err := c.get(base+"/manifests/"+digest, manifestTypes, &manifest); err != nil {
+			return imageInfo{}, err
+		}
+	}
+
+	var config struct {
+		Created time.Time `json:"created"`
+		History []struct {
+			Created   time.Time `json:"created"`
+			CreatedBy string    `json:"created_by"`
+		} `json:"history"`
+	}
+	if err := c.get(base+"/blobs/"+manifest.Config.Digest, []string{"*/*"}, &config); err != nil {
+		return imageInfo{}, err
+	}
+
+	info := imageInfo{source: r.registry, created: config.Created}
+	for _, h := range config.History {
+		info.layers = append(info.layers, imageLayer{h.Created, h.CreatedBy})
+	}
+	return info, nil
+}
+
+// runImageCommand reports the creation time and layer ages of an image
+func runImageCommand(args []string) error {
+	fs := newFlagSet("image")
+	out := addOutputFlags(fs, 1)
+	remote := fs.Bool("registry", false, "skip the local daemon and ask the registry")
+	platform := fs.String("platform", "linux/"+runtime.GOARCH, "platform to pick from multi-platform images")
+	timeout := 15 * time.Second
+	fs.Var(durationValue{&timeout}, "timeout", "timeout of each request")
+	positional, err := parseArgs("image", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("image requires one image reference (e.g. debian:bookworm)")
+	}
+	ref := positional[0]
+
+	var info imageInfo
+	daemonErr := errors.New("skipped")
+	if !*remote {
+		info, daemonErr = daemonImage(ref, timeout)
+	}
+	if daemonErr != nil {
+		var err error
+		if info, err = registryImage(ref, *platform, timeout); err != nil {
+			if *remote {
+				return fmt.Errorf("cannot inspect %s: %s", ref, err)
+			}
+			return fmt.Errorf("cannot inspect %s (daemon: %s; registry: %s)", ref, daemonErr, err)
+		}
+	}
+
+	if !isTTY() {
+		fmt.Printf("created\t%d\n", emitEpoch(info.created.UnixMilli()))
+		for _, l := range info.layers {
+			fmt.Printf("layer\t%d\t%s\n", emitEpoch(l.created.UnixMilli()), l.createdBy)
+		}
+		return nil
+	}
+
+	fmt.Printf("Image: %s (%s)\n", ref, info.source)
+	fmt.Printf("Created: %s (%s)\n", formatDateTime(info.created.Local(), false), timeAgo(info.created.UnixMilli(), out.precision))
+	if len(info.layers) > 0 {
+		fmt.Println("Layers:")
+	}
+	for _, l := range info.layers {
+		createdBy := strings.Join(strings.Fields(l.createdBy), " ")
+		if runes := []rune(createdBy); len(runes) > 60 {
+			createdBy = string(runes[:57]) + "..."
+		}
+		fmt.Printf("  %-20s %s\n", timeAgo(l.created.UnixMilli(), out.precision), createdBy)
+	}
+	return nil
+}
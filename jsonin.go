@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// jsonInputError reports an array element that is not a timestamp
+type jsonInputError struct {
+	Input json.RawMessage `json:"input"`
+	Error string          `json:"error"`
+}
+
+// convertJSONElement converts one array element (number or string)
+func convertJSONElement(raw json.RawMessage, f *timeago.Formatter) any {
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		// Not a string, keep the literal (numbers are written as-is)
+		value = string(raw)
+	}
+
+	epochMs, err := parseEpoch(strings.TrimSpace(value))
+	if err != nil {
+		return jsonInputError{Input: raw, Error: "invalid timestamp"}
+	}
+	return conversion(epochMs, f)
+}
+
+// conversion describes epochMs with f, or with the selected style when f
+// cannot render it, for JSON output
+func conversion(epochMs int64, f *timeago.Formatter) timeago.Conversion {
+	conv := timeago.NewConversion(time.UnixMilli(epochMs), f)
+	if outputStyle == "git" || outputStyle == "k8s" || outputStyle == "fuzzy" || outputStyle == "daily" {
+		conv.Relative = timeAgo(epochMs, 1)
+	}
+	conv.Epoch = emitEpoch(epochMs)
+	return conv
+}
+
+// runJSONIn streams a JSON array of timestamps from r and writes one
+// conversion object per element to w, as a JSON array or as NDJSON
+func runJSONIn(r io.Reader, w io.Writer, precision int, ndjson bool) error {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	dec.UseNumber()
+	out := bufio.NewWriter(w)
+	f := newFormatter(precision)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("invalid JSON input: %s", err)
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("invalid JSON input: expected an array")
+	}
+
+	count := 0
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return fmt.Errorf("invalid JSON input: %s", err)
+		}
+		data, err := json.Marshal(convertJSONElement(raw, f))
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case ndjson:
+		case count == 0:
+			out.WriteString("[\n  ")
+		default:
+			out.WriteString(",\n  ")
+		}
+		out.Write(data)
+		if ndjson {
+			out.WriteByte('\n')
+		}
+		count++
+	}
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("invalid JSON input: %s", err)
+	}
+
+	if !ndjson {
+		if count == 0 {
+			out.WriteString("[]\n")
+		} else {
+			out.WriteString("\n]\n")
+		}
+	}
+	return out.Flush()
+}
+
+// runJSONCommand converts a JSON array of timestamps from stdin
+func runJSONCommand(args []string) error {
+	fs := newFlagSet("json")
+	out := addOutputFlags(fs, 1)
+	ndjson := fs.Bool("ndjson", false, "write one JSON object per line")
+	if _, err := parseArgs("json", fs, args); err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	return runJSONIn(os.Stdin, os.Stdout, out.precision, *ndjson)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// jwtDescription details the jwt command for its usage
+const jwtDescription = `Decodes the payload of a JWT (the signature is NOT verified) and reports
+its exp, iat and nbf claims as absolute and relative times, with whether
+the token is currently valid. "-" reads the token from stdin; a "Bearer "
+prefix is ignored. Exits with status 2 when the token is expired or not
+valid yet, allowing --leeway of clock skew. Piped output is
+"claim<TAB>epoch" lines.`
+
+// jwtClaims are the registered time claims, in display order
+var jwtClaims = []struct{ name, label string }{
+	{"iat", "Issued at"},
+	{"nbf", "Not before"},
+	{"exp", "Expires"},
+}
+
+// jwtPayload decodes the claims of a compact JWS
+func jwtPayload(token string) (map[string]any, error) {
+	token = strings.TrimSpace(token)
+	if scheme, rest, ok := strings.Cut(token, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		token = strings.TrimSpace(rest)
+	}
+	parts := strings.Split(token, ".")
+	switch len(parts) {
+	case 3:
+	case 5:
+		return nil, errors.New("encrypted tokens (JWE) cannot be read without the key")
+	default:
+		return nil, fmt.Errorf("invalid JWT: expected 3 dot-separated parts, got %d", len(parts))
+	}
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT payload: %s", err)
+	}
+	var claims map[string]any
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return nil, fmt.Errorf("invalid JWT payload: %s", err)
+	}
+	return claims, nil
+}
+
+// numericDate reads a NumericDate claim: seconds since the epoch, possibly
+// fractional
+func numericDate(v any) (time.Time, bool) {
+	secs, ok := v.(float64)
+	if !ok || math.IsNaN(secs) || math.IsInf(secs, 0) {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(int64(math.Round(secs * 1000))), true
+}
+
+// runJWTCommand reports the time claims of a JWT
+func runJWTCommand(args []string) error {
+	fs := newFlagSet("jwt")
+	out := addOutputFlags(fs, 2)
+	var leeway time.Duration
+	fs.Var(durationValue{&leeway}, "leeway", "clock skew tolerated on exp and nbf")
+	positional, err := parseArgs("jwt", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New(`jwt requires one token, or "-" to read it from stdin`)
+	}
+
+	token := positional[0]
+	if token == "-" {
+		reader := bufio.NewReader(os.Stdin)
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("cannot read the token from stdin: %s", err)
+		}
+		token = line
+	}
+	claims, err := jwtPayload(token)
+	if err != nil {
+		return err
+	}
+
+	now := clock.Now()
+	times := map[string]time.Time{}
+	for _, c := range jwtClaims {
+		v, present := claims[c.name]
+		if !present {
+			continue
+		}
+		t, ok := numericDate(v)
+		if !ok {
+			return fmt.Errorf("invalid %s claim %v (expected seconds since the epoch)", c.name, v)
+		}
+		times[c.name] = t
+	}
+
+	exp, hasExp := times["exp"]
+	nbf, hasNbf := times["nbf"]
+	iat, hasIat := times["iat"]
+	expired := hasExp && !now.Before(exp.Add(leeway))
+	early := hasNbf && now.Before(nbf.Add(-leeway))
+
+	if !isTTY() {
+		for _, c := range jwtClaims {
+			if t, ok := times[c.name]; ok {
+				fmt.Printf("%s\t%d\n", c.name, emitEpoch(t.UnixMilli()))
+			}
+		}
+	} else {
+		for _, field := range []string{"iss", "sub", "aud"} {
+			if v, ok := claims[field]; ok {
+				fmt.Printf("%s: %v\n", field, v)
+			}
+		}
+		for _, c := range jwtClaims {
+			if t, ok := times[c.name]; ok {
+				fmt.Printf("%s (%s): %s (%s)\n", c.label, c.name, formatDateTime(t.Local(), false), timeAgo(t.UnixMilli(), out.precision))
+			}
+		}
+		if hasExp && hasIat {
+			fmt.Printf("Lifetime: %s\n", newFormatter(out.precision).Duration(exp.Sub(iat)))
+		}
+		switch {
+		case expired:
+			fmt.Printf("Status: EXPIRED %s\n", timeAgo(exp.UnixMilli(), out.precision))
+		case early:
+			fmt.Printf("Status: NOT YET VALID, valid %s\n", timeAgo(nbf.UnixMilli(), out.precision))
+		case !hasExp:
+			fmt.Println("Status: VALID, never expires (no exp claim)")
+		default:
+			fmt.Printf("Status: VALID, expires %s\n", timeAgo(exp.UnixMilli(), out.precision))
+		}
+		fmt.Println("Signature: not verified")
+	}
+
+	if expired || early {
+		return exitStatus(2)
+	}
+	return nil
+}
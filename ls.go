@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// lsDescription details the ls command for its usage
+const lsDescription = `Lists the entries of DIR (default: the current directory) with their
+modification times and ages; directories end with "/". --sort age puts the
+newest first. Piped output is "epoch<TAB>name" lines.`
+
+// lsEntry is one listed directory entry
+type lsEntry struct {
+	name    string
+	modTime time.Time
+}
+
+// runLsCommand lists a directory with modification ages
+func runLsCommand(args []string) error {
+	fs := newFlagSet("ls")
+	out := addOutputFlags(fs, 1)
+	all := fs.Bool("all", false, "include entries starting with a dot")
+	sortBy := fs.String("sort", "name", "order of the entries: name or age")
+	reverse := fs.Bool("reverse", false, "reverse the order")
+	var olderThan, newerThan time.Duration
+	fs.Var(durationValue{&olderThan}, "older-than", "only list entries modified at least this long ago")
+	fs.Var(durationValue{&newerThan}, "newer-than", "only list entries modified less than this long ago")
+	positional, err := parseArgs("ls", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		return errors.New("ls takes at most one directory")
+	}
+	if *sortBy != "name" && *sortBy != "age" {
+		return fmt.Errorf("unsupported --sort %q (supported: name, age)", *sortBy)
+	}
+	dir := "."
+	if len(positional) == 1 {
+		dir = positional[0]
+	}
+
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	now := clock.Now()
+	var entries []lsEntry
+	for _, d := range dirEntries {
+		if !*all && strings.HasPrefix(d.Name(), ".") {
+			continue
+		}
+		info, err := d.Info()
+		if err != nil {
+			// Removed since ReadDir
+			continue
+		}
+		age := now.Sub(info.ModTime())
+		if (olderThan > 0 && age < olderThan) || (newerThan > 0 && age >= newerThan) {
+			continue
+		}
+		name := d.Name()
+		if d.IsDir() {
+			name += "/"
+		}
+		entries = append(entries, lsEntry{name, info.ModTime()})
+	}
+
+	// ReadDir sorts by name already
+	if *sortBy == "age" {
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].modTime.After(entries[j].modTime)
+		})
+	}
+	if *reverse {
+		for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+			entries[i], entries[j] = entries[j], entries[i]
+		}
+	}
+
+	tty := isTTY()
+	for _, e := range entries {
+		if !tty {
+			fmt.Printf("%d\t%s\n", emitEpoch(e.modTime.UnixMilli()), e.name)
+			continue
+		}
+		fmt.Printf("%s  %-24s %s\n", formatDateTime(e.modTime, false), timeAgo(e.modTime.UnixMilli(), out.precision), e.name)
+	}
+	return nil
+}
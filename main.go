@@ -1,143 +1,299 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
+	"os/signal"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
+	"github.com/studiowebux/timeago/timeago"
 	"golang.org/x/term"
 )
 
-// formatDateTime formats a time as "YYYY-MM-DD HH:MM:SS"
+// formatDateTime formats a time as "YYYY-MM-DD HH:MM:SS", or with the
+// layout given on the command line
 func formatDateTime(t time.Time, utc bool) string {
 	if utc {
 		t = t.UTC()
 	}
-	return t.Format("2006-01-02 15:04:05")
+	return roundWallClock(t).Format(dateTimeLayout)
+}
+
+// dateTimeLayout is the Go layout of displayed absolute times, set with
+// --format or --layout
+var dateTimeLayout = "2006-01-02 15:04:05"
+
+// roundTo snaps displayed absolute times to a multiple of it (--round-to);
+// zero keeps them exact
+var roundTo time.Duration
+
+// roundWallClock rounds t to the nearest roundTo of its own wall clock, so
+// 5m steps land on :00, :05... even in zones offset by 30 or 45 minutes
+func roundWallClock(t time.Time) time.Time {
+	if roundTo <= 0 {
+		return t
+	}
+	_, offset := t.Zone()
+	shift := time.Duration(offset) * time.Second
+	return t.Add(shift).Round(roundTo).Add(-shift)
 }
 
 // parseTimeString parses a human-readable time string into milliseconds
 func parseTimeString(input string) (int64, error) {
-	input = strings.TrimSpace(input)
-	input = strings.TrimSuffix(input, "ago")
-	input = strings.TrimSpace(input)
-
-	// Try to parse as a plain number (milliseconds)
-	if val, err := strconv.ParseInt(input, 10, 64); err == nil {
-		return val, nil
-	}
-
-	// Time unit mappings
-	units := map[string]int64{
-		"year":        365 * 24 * 60 * 60 * 1000,
-		"years":       365 * 24 * 60 * 60 * 1000,
-		"y":           365 * 24 * 60 * 60 * 1000,
-		"month":       30 * 24 * 60 * 60 * 1000,
-		"months":      30 * 24 * 60 * 60 * 1000,
-		"week":        7 * 24 * 60 * 60 * 1000,
-		"weeks":       7 * 24 * 60 * 60 * 1000,
-		"w":           7 * 24 * 60 * 60 * 1000,
-		"day":         24 * 60 * 60 * 1000,
-		"days":        24 * 60 * 60 * 1000,
-		"d":           24 * 60 * 60 * 1000,
-		"hour":        60 * 60 * 1000,
-		"hours":       60 * 60 * 1000,
-		"h":           60 * 60 * 1000,
-		"minute":      60 * 1000,
-		"minutes":     60 * 1000,
-		"min":         60 * 1000,
-		"m":           60 * 1000,
-		"second":      1000,
-		"seconds":     1000,
-		"sec":         1000,
-		"s":           1000,
-		"millisecond": 1,
-		"milliseconds": 1,
-		"ms":          1,
-	}
-
-	// Pattern to match number followed by unit
-	re := regexp.MustCompile(`(\d+)\s*([a-zA-Z]+)`)
-	matches := re.FindAllStringSubmatch(input, -1)
-
-	if len(matches) == 0 {
-		return 0, fmt.Errorf("invalid time format: %s", input)
-	}
-
-	var total int64
-	for _, match := range matches {
-		value, err := strconv.ParseInt(match[1], 10, 64)
-		if err != nil {
-			return 0, fmt.Errorf("invalid number: %s", match[1])
-		}
+	d, err := timeago.ParseDuration(input)
+	if err != nil {
+		return 0, err
+	}
+	return d.Milliseconds(), nil
+}
 
-		unit := strings.ToLower(match[2])
-		multiplier, ok := units[unit]
-		if !ok {
-			return 0, fmt.Errorf("unknown time unit: %s", unit)
+// isoTimestamp matches ISO 8601 / RFC 3339 timestamps; without an offset
+// they are read in local time
+var isoTimestamp = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?$`)
+
+// gitISOLayout is the format of git log --date=iso
+const gitISOLayout = "2006-01-02 15:04:05 -0700"
+
+// parseEpoch parses a timestamp into epoch milliseconds. Integers are epochs
+// in the unit of epochToMs; ISO 8601, git's ISO dates and natural phrases
+// ("tomorrow 3pm") are accepted as well, followed by any number of
+// "+ DURATION" or "- DURATION" terms.
+func parseEpoch(input string) (int64, error) {
+	if epochMs, ok, err := parseExpression(input); ok {
+		return epochMs, err
+	}
+	if isoTimestamp.MatchString(input) {
+		t, err := time.ParseInLocation(isoLayout(input), input, time.Local)
+		return t.UnixMilli(), err
+	}
+	if t, err := time.Parse(gitISOLayout, input); err == nil {
+		return t.UnixMilli(), nil
+	}
+	epoch, err := strconv.ParseInt(input, 10, 64)
+	if err != nil {
+		var dateErr *timeago.ErrAmbiguousDate
+		if _, derr := timeago.ParseDate(input, time.Local); errors.As(derr, &dateErr) {
+			if canPrompt() {
+				return chooseReading(input, dateReadings(dateErr))
+			}
+			return 0, derr
+		}
+		if t, nerr := timeago.ParseTime(translateInput(input), clock.Now()); nerr == nil {
+			return t.UnixMilli(), nil
 		}
+		return 0, err
+	}
+	if epochBase != nil {
+		return *epochBase + offsetToMs(epoch), nil
+	}
+	if ambiguousEpoch(epoch) && canPrompt() {
+		return chooseReading(input, epochReadings(epoch))
+	}
+	return epochToMs(epoch), nil
+}
 
-		total += value * multiplier
+// translateInput rewrites a phrase in the --lang language, or in any
+// supported language with the default English, into English ("hace 2
+// horas" to "2 hours ago"); other input is returned unchanged
+func translateInput(input string) string {
+	lang := ""
+	if outputLang != "en" {
+		lang = outputLang
+	}
+	if english, err := timeago.Translate(input, lang); err == nil {
+		return english
 	}
+	return input
+}
 
-	return total, nil
+// clock is the reference of relative times, phrases and omitted
+// timestamps; --now replaces it with a fixed instant for reproducible
+// output
+var clock timeago.Clock = timeago.SystemClock{}
+
+// epochBase, when set (--epoch-base), makes integer timestamps offsets
+// from this instant in epoch milliseconds, e.g. for simulation clocks
+var epochBase *int64
+
+// offsetToMs converts an integer offset from epochBase to milliseconds.
+// Magnitudes say nothing about the unit of an offset, so auto reads
+// milliseconds, or seconds with the git style.
+func offsetToMs(n int64) int64 {
+	unit := epochUnit
+	if unit == "auto" {
+		unit = "ms"
+		if outputStyle == "git" {
+			unit = "s"
+		}
+	}
+	return unitToMs(n, unit)
 }
 
-// timeAgo converts an epoch timestamp to a human-readable relative time
-func timeAgo(epochMs int64, precision int) string {
-	now := time.Now().UnixMilli()
-	diff := now - epochMs
+// epochUnit is the unit of integer timestamps: "auto", "s", "ms", "us" or
+// "ns" (--unit)
+var epochUnit = "auto"
+
+// outUnit is the unit of epochs printed for scripts: piped output, JSON and
+// batches (--out-unit)
+var outUnit = "ms"
+
+// epochUnits are the units of --unit and --out-unit
+var epochUnits = []string{"s", "ms", "us", "ns"}
 
-	if diff == 0 {
-		return "just now"
+// setOutUnit validates and selects the --out-unit
+func setOutUnit(unit string) error {
+	if slices.Contains(epochUnits, unit) {
+		outUnit = unit
+		return nil
 	}
+	return fmt.Errorf("unsupported output unit %q (supported: %s)", unit, strings.Join(epochUnits, ", "))
+}
 
-	isFuture := diff < 0
-	if isFuture {
-		diff = -diff
+// emitEpoch converts epoch milliseconds to outUnit; seconds round down
+func emitEpoch(epochMs int64) int64 {
+	switch outUnit {
+	case "s":
+		return time.UnixMilli(epochMs).Unix()
+	case "us":
+		return epochMs * 1000
+	case "ns":
+		return epochMs * 1e6
 	}
+	return epochMs
+}
 
-	units := []struct {
-		name  string
-		value int64
-	}{
-		{"year", 365 * 24 * 60 * 60 * 1000},
-		{"month", 30 * 24 * 60 * 60 * 1000},
-		{"week", 7 * 24 * 60 * 60 * 1000},
-		{"day", 24 * 60 * 60 * 1000},
-		{"hour", 60 * 60 * 1000},
-		{"minute", 60 * 1000},
-		{"second", 1000},
+// epochToMs converts an integer timestamp to milliseconds. In auto mode the
+// unit follows the magnitude: below 1e11 is seconds (until year 5138),
+// then milliseconds, microseconds and nanoseconds; the git style reads
+// seconds like git log --date=unix.
+func epochToMs(n int64) int64 {
+	unit := epochUnit
+	if unit == "auto" {
+		magnitude := epochMagnitude(n)
+		switch {
+		case outputStyle == "git" || magnitude < 1e11:
+			unit = "s"
+		case magnitude < 1e14:
+			unit = "ms"
+		case magnitude < 1e17:
+			unit = "us"
+		default:
+			unit = "ns"
+		}
 	}
+	return unitToMs(n, unit)
+}
 
-	var parts []string
-	remaining := diff
+// unitToMs converts n of unit ("s", "ms", "us" or "ns") to milliseconds
+func unitToMs(n int64, unit string) int64 {
+	switch unit {
+	case "s":
+		return n * 1000
+	case "us":
+		return n / 1000
+	case "ns":
+		return n / 1e6
+	}
+	return n
+}
 
-	for _, unit := range units {
-		if remaining >= unit.value {
-			count := remaining / unit.value
-			remaining %= unit.value
+// epochMagnitude returns the absolute value of n, unsigned so that
+// math.MinInt64 does not come back negative
+func epochMagnitude(n int64) uint64 {
+	if n < 0 {
+		return -uint64(n)
+	}
+	return uint64(n)
+}
 
-			unitName := unit.name
-			if count > 1 {
-				unitName += "s"
-			}
-			parts = append(parts, fmt.Sprintf("%d %s", count, unitName))
+// describeParseError turns a parse error into a message for the user
+func describeParseError(err error) string {
+	var unitErr *timeago.ErrUnknownUnit
+	if errors.As(err, &unitErr) {
+		return fmt.Sprintf("Unknown time unit %q (supported: years, months, weeks, days, hours, minutes, seconds, milliseconds)", unitErr.Unit)
+	}
+	var dateErr *timeago.ErrAmbiguousDate
+	if errors.As(err, &dateErr) {
+		return fmt.Sprintf("Ambiguous date %q, use YYYY-MM-DD instead (%s)", dateErr.Input, dateErr)
+	}
+	var limitErr *timeago.ErrLimitExceeded
+	if errors.As(err, &limitErr) {
+		return fmt.Sprintf("Time value rejected: %s", limitErr)
+	}
+	var formatErr *timeago.ErrInvalidFormat
+	if errors.As(err, &formatErr) {
+		return fmt.Sprintf("Invalid time format: %s", formatErr.Input)
+	}
+	return fmt.Sprintf("Invalid time format: %s", err)
+}
 
-			if len(parts) >= precision {
-				break
-			}
-		}
+// outputStyle selects how relative times are worded: "long", "short",
+// "fuzzy", "speech", "git", "k8s" or "daily"
+var outputStyle = "long"
+
+// fuzzyFormatter renders the fuzzy style, with the thresholds of the config
+var fuzzyFormatter = timeago.NewFuzzy()
+
+// outputLang is the language of relative times, a key of timeago.Locales
+var outputLang = "en"
+
+// newFormatter returns the formatter for the selected output style
+func newFormatter(precision int) *timeago.Formatter {
+	f := timeago.NewFormatter()
+	if outputStyle == "speech" {
+		f = timeago.NewSpeechFormatter()
+	} else if outputStyle == "short" {
+		f = timeago.NewShortFormatter()
+	} else if outputLang != "en" {
+		// The language was validated with the flags
+		f, _ = timeago.NewLocaleFormatter(outputLang)
+	}
+	f = f.WithPrecision(precision)
+	f.Clock = clock
+	return f
+}
+
+// timeAgo converts an epoch timestamp to a human-readable relative time
+func timeAgo(epochMs int64, precision int) string {
+	return timeAgoAt(epochMs, precision, clock.Now())
+}
+
+// timeAgoAt is timeAgo relative to the given instant instead of now
+func timeAgoAt(epochMs int64, precision int, now time.Time) string {
+	switch outputStyle {
+	case "git":
+		return timeago.GitRelative(time.UnixMilli(epochMs), now)
+	case "k8s":
+		return timeago.KubeAge(now.Sub(time.UnixMilli(epochMs)))
+	case "fuzzy":
+		return fuzzyFormatter.RelativeTo(time.UnixMilli(epochMs), now)
+	case "daily":
+		return timeago.DailyRelative(time.UnixMilli(epochMs), now)
 	}
+	return newFormatter(precision).RelativeTo(time.UnixMilli(epochMs), now)
+}
 
-	result := strings.Join(parts, " ")
-	if isFuture {
-		return "in " + result
+// hoursFormatter renders durations in hours and below, which reads best for
+// time tracking ("40 hours" rather than "1 day 16 hours")
+func hoursFormatter(precision int) *timeago.Formatter {
+	f := newFormatter(precision)
+	f.Units = nil
+	for _, unit := range timeago.DefaultUnits {
+		if unit.Duration <= time.Hour {
+			f.Units = append(f.Units, unit)
+		}
 	}
-	return result + " ago"
+	return f
 }
 
 // isTTY checks if stdout is a terminal
@@ -147,237 +303,458 @@ func isTTY() bool {
 
 // printHelp displays usage information
 func printHelp() {
-	help := `Époque - Time manipulation utility
+	fmt.Print(`Époque - Time manipulation utility
 
 USAGE:
-  timeago [OPTIONS] [ARGUMENTS]
-
-MODES:
-  No arguments:
-    timeago
-    Shows current time in epoch, UTC, and local formats
-
-  Convert timestamp:
-    timeago <EPOCH_TIMESTAMP> [PRECISION]
-    Shows the timestamp in multiple formats with relative time
-    PRECISION: 1-7 (default: 1) - number of time units to display
-
-  Add time:
-    timeago --add <TIME> [EPOCH_TIMESTAMP] [PRECISION]
-    Adds time to current timestamp or specified timestamp
-    TIME: human-readable time (e.g., "2 hours", "30 minutes", "1 day 5 hours")
-
-  Remove time:
-    timeago --remove <TIME> [EPOCH_TIMESTAMP] [PRECISION]
-    Removes time from current timestamp or specified timestamp
-
-OPTIONS:
-  --help, -h     Show this help message
-  --add          Add time to a timestamp
-  --remove       Remove time from a timestamp
+  timeago <COMMAND> [OPTIONS] [ARGUMENTS]
+
+COMMANDS:
+`)
+	for _, c := range commands() {
+		fmt.Printf("  %-10s %s\n", c.name, c.summary)
+	}
+
+	help := `
+  Run "timeago <COMMAND> -h" for the options of a command. Output options
+  such as -p may also come before the command.
+
+LEGACY INVOCATION:
+  The original flat flags keep working and map onto the commands above:
+  timeago                                    -> timeago now
+  timeago <TIMESTAMP> [PRECISION]            -> timeago convert
+  timeago --add <TIME> [TIMESTAMP]           -> timeago add
+  timeago --remove <TIME> [TIMESTAMP]        -> timeago sub
+  timeago --filter [OPTIONS]                 -> timeago filter
+  timeago --json-in [OPTIONS]                -> timeago json
+  timeago --golden [OPTIONS]                 -> timeago golden
+  timeago --until <TARGET>                   -> timeago until
+  timeago --file <PATH> [--stat S]           -> timeago file
+  timeago --pid <PID>                        -> timeago pid
+  timeago <TS> --before|--after|--within ... -> timeago check <TS> ...
+  timeago --stdin [OPTIONS]                  -> timeago convert --stdin
+
+COMMON OPTIONS:
+  --help, -h     Show help (of a command when given after it)
   -p             Set precision (1-7, can be placed anywhere in arguments)
+  --style STYLE  Wording of relative times: long (default), short ("2h", "3d", "5mo"),
+                 fuzzy ("about 2 hours ago"), speech, git, k8s or daily; the flags below
+                 are shorthands
+  --speech       Word relative times for text-to-speech ("two hours and thirty minutes ago")
+  --git          Word relative times like git log --date=relative ("1 year, 2 months ago");
+                 integer timestamps are read as seconds (git log --date=unix)
+  --k8s          Render ages like kubectl's AGE column ("5d17h", two units, no spaces)
+  --daily        Render whole calendar days only ("today", "yesterday", "3 days ago"),
+                 counted between local midnights
+  --format FMT   Render absolute times with a strftime format ("%Y-%m-%dT%H:%M:%S%z")
+  --layout LAY   Render absolute times with a Go layout ("2006-01-02T15:04:05Z07:00")
+  --unit U       Unit of integer timestamps: auto (default, by magnitude), s, ms, us or ns
+  --no-prompt    Never ask which reading of an ambiguous timestamp (03/04/2024, ten
+                 digits) is meant; dates fail and ten digits are seconds
+  --epoch-base T Read integer timestamps as offsets from T (in --unit, ms by default)
+  --out-unit U   Unit of epochs in piped, batch and JSON output: s, ms (default), us or ns
+  --round-to D   Snap displayed absolute times to the nearest D (e.g. 5m); epochs stay exact
+  --lang LANG    Language of relative times: de, en, es, fr, it, ja, nl, pl, pt, ru, zh
+  --watch[=D]    convert: keep redrawing the relative time in place every second (or D)
+  --aria         convert/add/sub: print an accessible HTML <time> fragment
+  --pair-columns A,B
+                 convert --stdin: print the duration from column A to column B of each
+                 line (tab separated, or whitespace when there is no tab)
+  --template T   convert: render each timestamp with a Go text/template; fields are
+                 Input, Epoch, Seconds, UTC, Local, ISO, Relative and Time
+  --tz ZONE      now/convert/add/sub: also show the time in an IANA zone (repeatable)
+  --jitter D     add/sub/until: delay the result by a random offset in [0, D)
+  --trace        add/sub: show the base, each term, every step and the result on stderr
+  --force        add/sub: accept moves beyond --max-shift (default 100 years), which
+                 otherwise need a confirmation or fail when not interactive
 
 TIME FORMATS:
   Supported units: years, months, weeks, days, hours, minutes, seconds, milliseconds
   Abbreviated: y, w, d, h, m/min, s/sec, ms
-  Examples: "2 hours", "30 minutes", "1 day 5 hours", "2h 30m", "90s"
+  Examples: "2 hours", "30 minutes", "1 day 5 hours", "2h 30m", "90s", "1.5 hours"
+  ISO 8601: "P1DT2H30M", "PT45M", "PT1.5S" (as emitted by APIs and YAML configs)
+  Go syntax: "2h30m45s", "1500ms", "1.5h" (as in Go service configs and logs)
+  Signed terms: "-30 minutes", "1 day -2 hours" (add with a negative value goes back)
+  add/sub follow the calendar for years, months and days (Jan 31 + 1 month is
+  Feb 28/29); --fixed counts them as 365, 30 and 1 day instead
+  Timestamps: epochs (seconds, ms, µs or ns by magnitude; --unit forces one),
+  ISO 8601 / RFC 3339 ("2024-03-01T15:04:05Z", "2024-03-01 15:04:05" in local
+  time), git's --date=iso values, or phrases
+  ("yesterday", "tomorrow 3pm", "next tuesday", "last friday at noon", "2 hours ago")
 
 PRECISION:
   1-7: Number of time units to display in relative time
   Example: precision 2 shows "2 hours 30 minutes ago"
 
+CONFIG:
+  ~/.config/timeago/config.json (override with TIMEAGO_CONFIG)
+  "detectors": custom timestamp regexes and Go layouts for filter and shift
+  "fuzzy": thresholds of --style fuzzy ({"over": 0.1, "round_up": 0.5, "almost": 0.75})
+  "zones": IANA zones of zones and all without --zones/--tz (["UTC", "Asia/Tokyo"])
+
 PIPED OUTPUT:
   When output is piped, only the result epoch timestamp is printed
 
 EXAMPLES:
-  timeago                              # Show current time
-  timeago 1700000000000                # Convert epoch to human-readable
-  timeago 1700000000000 2              # Show with 2 units of precision (positional)
-  timeago 1700000000000 -p 2           # Show with 2 units of precision (flag)
-  timeago --add "2 hours"              # Add 2 hours to current time
-  timeago --add "2 hours" -p 3         # Add 2 hours with precision 3
-  timeago 1700000000000 --add "2 hours" -p 2  # Flexible argument order
-  timeago --add "1 day" 1700000000000  # Add 1 day to specific timestamp
-  timeago --remove "30 minutes"        # Remove 30 minutes from current time
+  timeago now                          # Show current time
+  timeago convert 1700000000000 -p 2   # Show with 2 units of precision
+  timeago add "2 hours"                # Add 2 hours to current time
+  timeago add "1 day" 1700000000000    # Add 1 day to specific timestamp
+  timeago sub "30 minutes"             # Remove 30 minutes from current time
+  timeago 1700000000000 --add "2 hours" -p 2  # Legacy flat invocation
+  tail app.log | timeago filter --since 2h --until 30m  # Window log lines
+  timeago shift --stdin --by -37d4h < app.log  # Shift all timestamps back
+  timeago budget 40h --spent 8h,7h30m  # Time left in a 40 hour week
+  timeago range "last week"            # Start and end epochs of last week
 `
 	fmt.Print(help)
 }
 
-func main() {
-	args := os.Args[1:]
+// runNow shows the current time
+func runNow(args []string) error {
+	fs := newFlagSet("now")
+	var zones zonesValue
+	fs.Var(&zones, "tz", "also show the time in this IANA zone (repeatable)")
+	unit := fs.String("out-unit", "ms", "unit of the piped epoch: s, ms, us or ns")
+	if _, err := parseArgs("now", fs, args); err != nil {
+		return err
+	}
+	if err := setOutUnit(*unit); err != nil {
+		return err
+	}
 
-	// Handle help
-	for _, arg := range args {
-		if arg == "--help" || arg == "-h" {
-			printHelp()
-			os.Exit(0)
-		}
+	now := clock.Now()
+	epochMs := now.UnixMilli()
+
+	if isTTY() {
+		fmt.Println("Current Time:")
+		fmt.Printf("Epoch: %d\n", epochMs)
+		fmt.Printf("UTC: %s\n", formatDateTime(now, true))
+		fmt.Printf("Local: %s\n", formatDateTime(now, false))
+		printZones(now, zones)
+	} else {
+		fmt.Println(emitEpoch(epochMs))
 	}
+	return nil
+}
 
-	isTTY := isTTY()
+// printZones prints t in each of the zones given with --tz
+func printZones(t time.Time, zones zonesValue) {
+	for _, loc := range zones {
+		fmt.Printf("%s: %s\n", loc, formatInZone(t, loc))
+	}
+}
 
-	// Handle no arguments - show current time
-	if len(args) == 0 {
-		now := time.Now()
-		epochMs := now.UnixMilli()
+// legacyPrecision reads a trailing positional precision (1-7), accepted for
+// backward compatibility when -p is not given
+func legacyPrecision(out *outputFlags, arg string) {
+	if out.set {
+		return
+	}
+	if p, err := strconv.Atoi(arg); err == nil && p >= 1 && p <= 7 {
+		out.precision = p
+	}
+}
 
-		if isTTY {
-			fmt.Println("Current Time:")
-			fmt.Printf("Epoch: %d\n", epochMs)
-			fmt.Printf("UTC: %s\n", formatDateTime(now, true))
-			fmt.Printf("Local: %s\n", formatDateTime(now, false))
-		} else {
-			fmt.Println(epochMs)
+// runConvert shows a timestamp in multiple formats with relative time
+func runConvert(args []string) error {
+	fs := newFlagSet("convert")
+	out := addOutputFlags(fs, 1)
+	aria := fs.Bool("aria", false, "print an accessible HTML <time> fragment")
+	stdin := fs.Bool("stdin", false, "convert one timestamp per line read from stdin")
+	var watch time.Duration
+	fs.Var(watchValue{&watch}, "watch", "keep redrawing the relative time in place (--watch=5s for another interval)")
+	var pair pairColumns
+	fs.Var(&pair, "pair-columns", "with --stdin: print the duration between two columns of each line, e.g. 1,2")
+	maxLineBytes := fs.Int("max-line-bytes", 1024*1024, "with --stdin: report and skip longer lines")
+	progress := fs.Bool("progress", false, "with --stdin: report the bytes processed on stderr")
+	astro := addAstroFlags(fs)
+	templateText := fs.String("template", "", "render each timestamp with a Go text/template, e.g. '{{.Epoch}} {{.Relative}}'")
+	var zones zonesValue
+	fs.Var(&zones, "tz", "also show the time in this IANA zone (repeatable)")
+	positional, err := parseArgs("convert", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if *aria && *templateText != "" {
+		return errors.New("--aria and --template cannot be combined")
+	}
+	var tmpl *template.Template
+	if *templateText != "" {
+		if tmpl, err = parseOutputTemplate(*templateText); err != nil {
+			return err
 		}
-		os.Exit(0)
 	}
-
-	// Find operation flag (--add or --remove) anywhere in args
-	var operation string
-	var operationIdx int = -1
-	for i, arg := range args {
-		if arg == "--add" || arg == "--remove" {
-			operation = arg
-			operationIdx = i
-			break
+	if pair[0] != 0 && (!*stdin || *aria || tmpl != nil) {
+		return errors.New("--pair-columns requires --stdin and cannot be combined with --aria or --template")
+	}
+	if watch > 0 && (*stdin || *aria || tmpl != nil || !isTTY()) {
+		return errors.New("--watch requires a terminal and cannot be combined with --stdin, --aria or --template")
+	}
+	if err := astro.check(); err != nil {
+		return err
+	}
+	if astro.on && (*stdin || *aria || tmpl != nil || watch > 0) {
+		return errors.New("--astro cannot be combined with --stdin, --aria, --template or --watch")
+	}
+	if (*progress || *maxLineBytes != 1024*1024) && !*stdin {
+		return errors.New("--progress and --max-line-bytes require --stdin")
+	}
+	if *stdin {
+		if len(positional) > 0 {
+			legacyPrecision(out, positional[0])
 		}
+		if *maxLineBytes < 1 {
+			return errors.New("--max-line-bytes requires a positive number")
+		}
+		var input io.Reader = os.Stdin
+		if *progress {
+			var stop func()
+			input, stop = withProgress(input)
+			defer stop()
+		}
+		if pair[0] != 0 {
+			return runPairLines(input, os.Stdout, *maxLineBytes, pair, out.precision, isTTY())
+		}
+		format := batchFormat(out.precision, *aria, isTTY())
+		if tmpl != nil {
+			format = templateFormat(tmpl, out.precision)
+		}
+		return runConvertLines(input, os.Stdout, *maxLineBytes, format)
+	}
+	if len(positional) == 0 {
+		return errors.New("convert requires a timestamp")
 	}
 
-	// Find precision flag (-p) anywhere in args
-	precision := 1
-	precisionIdx := -1
-	for i, arg := range args {
-		if arg == "-p" {
-			precisionIdx = i
-			if i+1 < len(args) {
-				p, err := strconv.Atoi(args[i+1])
-				if err == nil && p >= 1 && p <= 7 {
-					precision = p
-				} else {
-					fmt.Fprintf(os.Stderr, "Error: -p requires a value between 1 and 7\n")
-					os.Exit(1)
-				}
-			} else {
-				fmt.Fprintf(os.Stderr, "Error: -p requires a value\n")
-				os.Exit(1)
-			}
-			break
-		}
+	epochMs, err := parseEpoch(positional[0])
+	if err != nil {
+		return fmt.Errorf("Invalid timestamp %q (expected epoch milliseconds, ISO 8601 or a date phrase)", positional[0])
 	}
+	if len(positional) > 1 {
+		legacyPrecision(out, positional[1])
+	}
+	precision := out.precision
 
-	// Handle --add or --remove operations
-	if operationIdx >= 0 {
-		// Find the time value (should be right after the flag)
-		if operationIdx+1 >= len(args) {
-			fmt.Fprintf(os.Stderr, "Error: %s requires a time value\n", operation)
-			os.Exit(1)
-		}
+	t := time.UnixMilli(epochMs)
 
-		timeStr := args[operationIdx+1]
-		timeMs, err := parseTimeString(timeStr)
+	if tmpl != nil {
+		line, err := templateFormat(tmpl, precision)(positional[0], epochMs)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: Invalid time format: %s\n", err)
-			os.Exit(1)
+			return err
+		}
+		fmt.Println(line)
+	} else if *aria {
+		fmt.Println(ariaFragment(epochMs, precision))
+	} else if isTTY() {
+		fmt.Printf("Epoch: %d\n", epochMs)
+		fmt.Printf("UTC: %s\n", formatDateTime(t, true))
+		fmt.Printf("Local: %s\n", formatDateTime(t, false))
+		printZones(t, zones)
+		fmt.Printf("Precision: %d\n", precision)
+		if watch > 0 {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			return watchRelative(ctx, epochMs, precision, watch)
 		}
+		fmt.Printf("Time ago: %s\n", timeAgo(epochMs, precision))
+		if astro.on {
+			for _, f := range astro.fields(t, precision) {
+				fmt.Printf("%s: %s\n", f[0], f[1])
+			}
+		}
+	} else {
+		fmt.Println(emitEpoch(epochMs))
+	}
+	return nil
+}
 
-		// Find timestamp from remaining args (skip operation, time value, and -p flag)
-		var baseEpoch int64 = -1
+// runAdd adds time to now or to a timestamp
+func runAdd(args []string) error {
+	return runOffset("add", args)
+}
 
-		for i, arg := range args {
-			// Skip the operation flag and its time value
-			if i == operationIdx || i == operationIdx+1 {
-				continue
-			}
-			// Skip -p flag and its value
-			if i == precisionIdx || i == precisionIdx+1 {
-				continue
-			}
+// runSub removes time from now or from a timestamp
+func runSub(args []string) error {
+	return runOffset("sub", args)
+}
 
-			// Try to parse as timestamp
-			val, err := strconv.ParseInt(arg, 10, 64)
-			if err == nil {
-				// If no -p flag was found and it's 1-7, treat as precision for backward compatibility
-				if precisionIdx == -1 && val >= 1 && val <= 7 && baseEpoch != -1 {
-					precision = int(val)
-				} else {
-					// Otherwise it's a timestamp
-					baseEpoch = val
-				}
-			}
+// runOffset implements add and sub
+func runOffset(name string, args []string) error {
+	fs := newFlagSet(name)
+	out := addOutputFlags(fs, 1)
+	aria := fs.Bool("aria", false, "print an accessible HTML <time> fragment")
+	fixed := fs.Bool("fixed", false, "count months and years as 30 and 365 days instead of calendar units")
+	var jitter time.Duration
+	fs.Var(durationValue{&jitter}, "jitter", jitterUsage)
+	trace := fs.Bool("trace", false, "show each arithmetic step on stderr")
+	maxShift := 100 * 365 * 24 * time.Hour
+	fs.Var(durationValue{&maxShift}, "max-shift", "largest move accepted without --force or confirmation")
+	force := fs.Bool("force", false, "accept moves beyond --max-shift")
+	var zones zonesValue
+	fs.Var(&zones, "tz", "also show the time in this IANA zone (repeatable)")
+	positional, err := parseArgs(name, fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return fmt.Errorf("%s requires a time value", name)
+	}
+
+	value := translateInput(positional[0])
+	timeMs, err := parseTimeString(value)
+	if err != nil {
+		return errors.New(describeParseError(err))
+	}
+	calendar, err := timeago.ParseCalendarDuration(value)
+	if err != nil {
+		return errors.New(describeParseError(err))
+	}
+
+	// Find timestamp from remaining args
+	var baseEpoch int64 = -1
+	for _, arg := range positional[1:] {
+		// A 1-7 value after the timestamp is a legacy positional precision
+		if p, err := strconv.Atoi(arg); err == nil && baseEpoch != -1 && !out.set && p >= 1 && p <= 7 {
+			out.precision = p
+			continue
+		}
+		val, err := parseEpoch(arg)
+		if err != nil {
+			continue
 		}
+		baseEpoch = val
+	}
+	precision := out.precision
 
-		// Use current time if no timestamp specified
-		if baseEpoch == -1 {
-			baseEpoch = time.Now().UnixMilli()
+	// Use current time if no timestamp specified
+	if baseEpoch == -1 {
+		baseEpoch = clock.Now().UnixMilli()
+	}
+
+	// Calculate new timestamp. Calendar units follow month lengths, leap
+	// years and DST in the local zone unless --fixed is given.
+	var newEpoch int64
+	switch {
+	case *fixed && name == "add":
+		newEpoch = baseEpoch + timeMs
+	case *fixed:
+		newEpoch = baseEpoch - timeMs
+	case name == "add":
+		newEpoch = calendar.Add(time.UnixMilli(baseEpoch)).UnixMilli()
+	default:
+		newEpoch = calendar.Sub(time.UnixMilli(baseEpoch)).UnixMilli()
+	}
+	// A unit typo ("200 y" for "200 d") should not silently feed a
+	// timestamp centuries away to the scripts consuming the output
+	if shift := time.Duration(max(newEpoch-baseEpoch, baseEpoch-newEpoch)) * time.Millisecond; shift > maxShift && !*force {
+		question := fmt.Sprintf("%q moves the timestamp by %s, more than --max-shift %s", positional[0],
+			newFormatter(1).Duration(shift), newFormatter(1).Duration(maxShift))
+		if !canPrompt() {
+			return fmt.Errorf("%s; pass --force if this is intended", question)
 		}
+		if !confirm(question + ". Continue?") {
+			return errors.New("cancelled")
+		}
+	}
 
-		// Calculate new timestamp
-		var newEpoch int64
-		if operation == "--add" {
-			newEpoch = baseEpoch + timeMs
-		} else {
-			newEpoch = baseEpoch - timeMs
+	jitterMs := randomJitter(jitter).Milliseconds()
+	newEpoch += jitterMs
+	if *trace {
+		jitterD := time.Duration(jitterMs) * time.Millisecond
+		if err := traceOffset(name, value, time.UnixMilli(baseEpoch), calendar, *fixed, jitterD); err != nil {
+			return err
 		}
+	}
 
-		// Output result
-		if isTTY {
-			operationLabel := "Time Added"
-			if operation == "--remove" {
-				operationLabel = "Time Removed"
-			}
+	// A negative value turns an addition into a removal and vice versa
+	operationLabel := "Time Added"
+	timeMs = newEpoch - baseEpoch
+	if timeMs < 0 {
+		operationLabel = "Time Removed"
+		timeMs = -timeMs
+	}
 
-			newTime := time.UnixMilli(newEpoch)
-			fmt.Printf("Base Timestamp: %d\n", baseEpoch)
-			fmt.Printf("%s: %d ms\n", operationLabel, timeMs)
-			fmt.Printf("New Timestamp: %d\n", newEpoch)
-			fmt.Printf("UTC: %s\n", formatDateTime(newTime, true))
-			fmt.Printf("Local: %s\n", formatDateTime(newTime, false))
-			fmt.Printf("Precision: %d\n", precision)
-			fmt.Printf("Time %s: %s\n",
-				map[bool]string{true: "until", false: "ago"}[newEpoch > time.Now().UnixMilli()],
-				timeAgo(newEpoch, precision))
-		} else {
-			fmt.Println(newEpoch)
+	// Output result
+	if *aria {
+		fmt.Println(ariaFragment(newEpoch, precision))
+	} else if isTTY() {
+		newTime := time.UnixMilli(newEpoch)
+		fmt.Printf("Base Timestamp: %d\n", baseEpoch)
+		fmt.Printf("%s: %d ms\n", operationLabel, timeMs)
+		if jitter > 0 {
+			fmt.Printf("Jitter: %d ms\n", jitterMs)
 		}
-		os.Exit(0)
+		fmt.Printf("New Timestamp: %d\n", newEpoch)
+		fmt.Printf("UTC: %s\n", formatDateTime(newTime, true))
+		fmt.Printf("Local: %s\n", formatDateTime(newTime, false))
+		printZones(newTime, zones)
+		fmt.Printf("Precision: %d\n", precision)
+		fmt.Printf("Time %s: %s\n",
+			map[bool]string{true: "until", false: "ago"}[newEpoch > clock.Now().UnixMilli()],
+			timeAgo(newEpoch, precision))
+	} else {
+		fmt.Println(emitEpoch(newEpoch))
 	}
+	return nil
+}
 
-	// Handle timestamp conversion (no operation flag)
-	var epochMs int64
-	var err error
+func main() {
+	args := os.Args[1:]
 
-	// Find the timestamp (skip -p flag and its value)
-	for i, arg := range args {
-		if i == precisionIdx || i == precisionIdx+1 {
-			continue
+	// Output options given before the subcommand are parsed with its own
+	name, rest := "", args
+	if i := commandIndex(args); i >= 0 {
+		name, rest = args[i], slices.Concat(args[:i], args[i+1:])
+	}
+
+	// Handle help (subcommands print their own usage)
+	if len(args) > 0 && args[0] == "help" {
+		if len(args) > 1 {
+			if _, ok := findCommand(args[1]); ok {
+				name, rest = args[1], []string{"-h"}
+			}
+		}
+		if name == "" {
+			printHelp()
+			os.Exit(0)
 		}
-		epochMs, err = strconv.ParseInt(arg, 10, 64)
-		if err == nil {
-			// If no -p was specified and there's another arg that's 1-7, use it as precision
-			if precisionIdx == -1 && i+1 < len(args) {
-				p, errP := strconv.Atoi(args[i+1])
-				if errP == nil && p >= 1 && p <= 7 {
-					precision = p
-				}
+	}
+	if name == "" {
+		for _, arg := range args {
+			if arg == "--help" || arg == "-h" {
+				printHelp()
+				os.Exit(0)
 			}
-			break
 		}
+		name, rest = legacyCommand(args)
 	}
 
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Invalid epoch timestamp\n")
+	checkLocalZone()
+	c, _ := findCommand(name)
+	err := c.run(rest)
+
+	var exitErr *exec.ExitError
+	var status exitStatus
+	var statusErr statusError
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, context.Canceled):
+		os.Exit(130)
+	case errors.As(err, &status):
+		os.Exit(int(status))
+	case errors.As(err, &exitErr):
+		os.Exit(exitErr.ExitCode())
+	case errors.As(err, &statusErr):
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(statusErr.status)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
 		os.Exit(1)
 	}
-
-	t := time.UnixMilli(epochMs)
-
-	if isTTY {
-		fmt.Printf("Epoch: %d\n", epochMs)
-		fmt.Printf("UTC: %s\n", formatDateTime(t, true))
-		fmt.Printf("Local: %s\n", formatDateTime(t, false))
-		fmt.Printf("Precision: %d\n", precision)
-		fmt.Printf("Time ago: %s\n", timeAgo(epochMs, precision))
-	} else {
-		fmt.Println(epochMs)
-	}
 }
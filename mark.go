@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// markDescription details the mark and elapsed commands for their usage
+const markDescription = `Marks are kept in TIMEAGO_MARKS or the user cache directory. On Linux the
+elapsed time is measured on the boot clock while the machine has not
+rebooted, so it survives wall-clock changes (NTP steps, DST, manual edits).`
+
+// markEntry is one named reference time
+type markEntry struct {
+	Epoch  int64   `json:"epoch"`
+	Uptime float64 `json:"uptime,omitempty"` // seconds since boot, /proc/uptime
+	BootID string  `json:"boot_id,omitempty"`
+}
+
+// marksPath returns the marks file location, honoring TIMEAGO_MARKS
+func marksPath() (string, error) {
+	if path := os.Getenv("TIMEAGO_MARKS"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "timeago", "marks.json"), nil
+}
+
+// loadMarks reads the marks file; a missing file yields no marks
+func loadMarks(path string) (map[string]markEntry, error) {
+	marks := map[string]markEntry{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return marks, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &marks); err != nil {
+		return nil, fmt.Errorf("invalid marks file %s: %s", path, err)
+	}
+	return marks, nil
+}
+
+// saveMarks writes the marks file through a rename, so concurrent readers
+// never see a partial file
+func saveMarks(path string, marks map[string]markEntry) error {
+	data, err := json.MarshalIndent(marks, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp" + strconv.Itoa(os.Getpid())
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// bootClock returns the seconds since boot and the boot ID, or zero values
+// where the system does not expose them
+func bootClock() (float64, string) {
+	data, err := os.ReadFile("/proc/uptime")
+	if err != nil {
+		return 0, ""
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, ""
+	}
+	uptime, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, ""
+	}
+	id, err := os.ReadFile("/proc/sys/kernel/random/boot_id")
+	if err != nil {
+		return 0, ""
+	}
+	return uptime, strings.TrimSpace(string(id))
+}
+
+// newMark records the current time
+func newMark() markEntry {
+	uptime, bootID := bootClock()
+	return markEntry{Epoch: clock.Now().UnixMilli(), Uptime: uptime, BootID: bootID}
+}
+
+// since returns the time elapsed from the mark to now, and whether it was
+// measured on the boot clock
+func (m markEntry) since(now markEntry) (time.Duration, bool) {
+	if m.BootID != "" && m.BootID == now.BootID {
+		return time.Duration((now.Uptime - m.Uptime) * float64(time.Second)).Round(time.Millisecond), true
+	}
+	return time.Duration(now.Epoch-m.Epoch) * time.Millisecond, false
+}
+
+// runMarkCommand stores the current time under a name
+func runMarkCommand(args []string) error {
+	fs := newFlagSet("mark")
+	positional, err := parseArgs("mark", fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || positional[0] == "" {
+		return errors.New("mark requires one name")
+	}
+	name := positional[0]
+
+	path, err := marksPath()
+	if err != nil {
+		return err
+	}
+	marks, err := loadMarks(path)
+	if err != nil {
+		return err
+	}
+	mark := newMark()
+	marks[name] = mark
+	if err := saveMarks(path, marks); err != nil {
+		return err
+	}
+
+	if isTTY() {
+		fmt.Printf("Marked %s at %s\n", name, formatDateTime(time.UnixMilli(mark.Epoch), false))
+	} else {
+		fmt.Println(mark.Epoch)
+	}
+	return nil
+}
+
+// runElapsedCommand reports the time since a mark
+func runElapsedCommand(args []string) error {
+	fs := newFlagSet("elapsed")
+	out := addOutputFlags(fs, 2)
+	positional, err := parseArgs("elapsed", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("elapsed requires one mark name")
+	}
+	name := positional[0]
+
+	path, err := marksPath()
+	if err != nil {
+		return err
+	}
+	marks, err := loadMarks(path)
+	if err != nil {
+		return err
+	}
+	mark, ok := marks[name]
+	if !ok {
+		return fmt.Errorf("no mark named %q (set one with: timeago mark %s)", name, name)
+	}
+
+	elapsed, monotonic := mark.since(newMark())
+	if !isTTY() {
+		fmt.Println(elapsed.Milliseconds())
+		return nil
+	}
+	source := "wall clock"
+	if monotonic {
+		source = "boot clock"
+	}
+	fmt.Printf("Mark: %s (%s)\n", name, formatDateTime(time.UnixMilli(mark.Epoch), false))
+	fmt.Printf("Elapsed: %s (%s)\n", newFormatter(out.precision).Duration(elapsed), source)
+	return nil
+}
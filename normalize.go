@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// normalizeDescription details the normalize command for its usage
+const normalizeDescription = `Rewrites each duration in its largest units with the selected style and
+language, e.g. "90 minutes" as "1 hour 30 minutes" or "5000 ms" as "5
+seconds". Without arguments, durations are read from stdin, one per line;
+blank lines are kept. Parts below a second are dropped.`
+
+// normalizeDuration renders d in its largest units
+func normalizeDuration(d time.Duration, precision int) string {
+	switch outputStyle {
+	case "k8s":
+		return timeago.KubeAge(d)
+	case "fuzzy":
+		return fuzzyFormatter.Duration(d)
+	}
+	return newFormatter(precision).Duration(d)
+}
+
+// normalizeLine parses and renders one duration
+func normalizeLine(input string, precision int) (string, error) {
+	d, err := timeago.ParseDuration(translateInput(input))
+	if err != nil {
+		return "", errors.New(describeParseError(err))
+	}
+	if d < 0 {
+		return "", fmt.Errorf("cannot normalize the negative duration %q", input)
+	}
+	return normalizeDuration(d, precision), nil
+}
+
+// runNormalizeCommand rewrites durations in their largest units
+func runNormalizeCommand(args []string) error {
+	fs := newFlagSet("normalize")
+	out := addOutputFlags(fs, 7)
+	positional, err := parseArgs("normalize", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+
+	if len(positional) > 0 {
+		for _, arg := range positional {
+			line, err := normalizeLine(arg, out.precision)
+			if err != nil {
+				return err
+			}
+			fmt.Println(line)
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		input := strings.TrimSpace(scanner.Text())
+		if input == "" {
+			fmt.Println()
+			continue
+		}
+		line, err := normalizeLine(input, out.precision)
+		if err != nil {
+			return err
+		}
+		fmt.Println(line)
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// ntpDescription details the ntp command for its usage
+const ntpDescription = `Queries an NTP server over SNTP (UDP port 123, default pool.ntp.org) and
+reports its time and the offset of the local clock, corrected for the
+network delay. Exits with status 2 when the offset exceeds --max-offset.
+Piped output is the server epoch and the offset in ms (server minus local),
+separated by a tab.`
+
+// ntpEpochOffset is the number of seconds from 1900, the NTP era, to 1970
+const ntpEpochOffset = 2208988800
+
+// ntpTime converts a 64-bit NTP timestamp
+func ntpTime(b []byte) time.Time {
+	secs := int64(binary.BigEndian.Uint32(b)) - ntpEpochOffset
+	frac := int64(binary.BigEndian.Uint32(b[4:]))
+	return time.Unix(secs, frac*1e9>>32)
+}
+
+// ntpResponse is what the ntp command reads from a server reply
+type ntpResponse struct {
+	stratum int
+	refID   string
+	time    time.Time // server time when the reply reached us
+	offset  time.Duration
+	delay   time.Duration
+}
+
+// queryNTP sends one SNTP client request to addr and applies the on-wire
+// calculation of RFC 4330 to the reply
+func queryNTP(addr string, timeout time.Duration) (ntpResponse, error) {
+	var r ntpResponse
+	conn, err := net.DialTimeout("udp", addr, timeout)
+	if err != nil {
+		return r, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	// LI 0, version 4, mode 3 (client); the transmit timestamp is random,
+	// which keeps the local time private and ties the reply to the request
+	req := make([]byte, 48)
+	req[0] = 0<<6 | 4<<3 | 3
+	rand.Read(req[40:48])
+
+	t0 := time.Now()
+	if _, err := conn.Write(req); err != nil {
+		return r, err
+	}
+	resp := make([]byte, 48)
+	var n int
+	for {
+		if n, err = conn.Read(resp); err != nil {
+			return r, err
+		}
+		if n >= 48 && string(resp[24:32]) == string(req[40:48]) {
+			break
+		}
+		// Stale or spoofed replies are ignored until the deadline
+	}
+	t3 := time.Now()
+
+	switch {
+	case resp[0]&7 != 4 && resp[0]&7 != 5:
+		return r, fmt.Errorf("unexpected NTP mode %d in reply", resp[0]&7)
+	case resp[1] == 0:
+		// Kiss-o'-Death: the reference ID is an ASCII code such as RATE
+		return r, fmt.Errorf("server refused the query (kiss code %s)", strings.TrimRight(string(resp[12:16]), "\x00"))
+	case resp[0]>>6 == 3:
+		return r, errors.New("server clock is not synchronized")
+	}
+
+	t1, t2 := ntpTime(resp[32:40]), ntpTime(resp[40:48])
+	r.stratum = int(resp[1])
+	if r.stratum == 1 {
+		r.refID = strings.TrimRight(string(resp[12:16]), "\x00")
+	} else {
+		r.refID = net.IP(resp[12:16]).String()
+	}
+	r.offset = (t1.Sub(t0) + t2.Sub(t3)) / 2
+	r.delay = t3.Sub(t0) - t2.Sub(t1)
+	r.time = t3.Add(r.offset)
+	return r, nil
+}
+
+// runNTPCommand reports the time of an NTP server and the local offset
+func runNTPCommand(args []string) error {
+	fs := newFlagSet("ntp")
+	out := addOutputFlags(fs, 2)
+	timeout := 5 * time.Second
+	fs.Var(durationValue{&timeout}, "timeout", "how long to wait for the reply")
+	var maxOffset time.Duration
+	fs.Var(durationValue{&maxOffset}, "max-offset", "exit with status 2 when the clock is off by more than this (0 disables)")
+	positional, err := parseArgs("ntp", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) > 1 {
+		return errors.New("ntp takes at most one SERVER[:PORT]")
+	}
+
+	addr := "pool.ntp.org"
+	if len(positional) == 1 {
+		addr = positional[0]
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(strings.Trim(addr, "[]"), "123")
+	}
+
+	r, err := queryNTP(addr, timeout)
+	if err != nil {
+		return fmt.Errorf("cannot query %s: %s", addr, err)
+	}
+
+	if isTTY() {
+		// Offsets are usually well under a second
+		f := newFormatter(out.precision)
+		f.Units = breakdownUnits
+		fmt.Printf("Server: %s (stratum %d, reference %s)\n", addr, r.stratum, r.refID)
+		fmt.Printf("NTP time: %s UTC\n", r.time.UTC().Format("2006-01-02 15:04:05.000"))
+		fmt.Printf("Epoch: %d\n", emitEpoch(r.time.UnixMilli()))
+		switch offset := r.offset.Round(time.Millisecond); {
+		case offset == 0:
+			fmt.Println("Offset: none, the local clock is within a millisecond")
+		case offset > 0:
+			fmt.Printf("Offset: the local clock is %s behind\n", f.Duration(offset))
+		default:
+			fmt.Printf("Offset: the local clock is %s ahead\n", f.Duration(offset))
+		}
+		fmt.Printf("Round trip: %s\n", r.delay.Round(time.Microsecond))
+	} else {
+		fmt.Printf("%d\t%d\n", emitEpoch(r.time.UnixMilli()), r.offset.Milliseconds())
+	}
+
+	if maxOffset > 0 && r.offset.Abs() > maxOffset {
+		return exitStatus(2)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// packagesDescription details the packages command for its usage
+const packagesDescription = `Humanizes the build and install dates printed by rpm and dpkg-query:
+epoch seconds (%{BUILDTIME}, %{INSTALLTIME}, ${db-fsys:Last-Modified}) and
+rpm's :date format. Takes the same options as filter.`
+
+// packageDateConfigs are rpm's :date renderings, in the C locale with and
+// without a 12-hour clock, and the ctime style of older releases
+var packageDateConfigs = []detectorConfig{
+	{
+		Name:    "rpm-date",
+		Pattern: `\b(?:Mon|Tue|Wed|Thu|Fri|Sat|Sun) \d{2} (?:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec) \d{4} \d{2}:\d{2}:\d{2}(?: [AP]M)?(?: [A-Z]{3,5})?`,
+		Layouts: []string{
+			"Mon 02 Jan 2006 03:04:05 PM MST",
+			"Mon 02 Jan 2006 15:04:05 MST",
+			"Mon 02 Jan 2006 03:04:05 PM",
+			"Mon 02 Jan 2006 15:04:05",
+		},
+	},
+	{
+		Name:    "ctime",
+		Pattern: `\b(?:Mon|Tue|Wed|Thu|Fri|Sat|Sun) (?:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec) [ \d]\d \d{2}:\d{2}:\d{2} \d{4}\b`,
+		Layouts: []string{time.ANSIC},
+	},
+}
+
+// epochSecondsDetector matches 10-digit epoch seconds, which package
+// databases use but which are too ambiguous for the general filter
+var epochSecondsDetector = timestampDetector{
+	name: "epoch-s",
+	re:   regexp.MustCompile(`\b\d{10}\b`),
+	parse: func(s string) (time.Time, error) {
+		secs, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(secs, 0), nil
+	},
+	format: func(t time.Time, original string) string {
+		return strconv.FormatInt(t.Unix(), 10)
+	},
+}
+
+// runPackagesCommand humanizes package database dates read from stdin
+func runPackagesCommand(args []string) error {
+	detectors := []timestampDetector{epochSecondsDetector}
+	for _, dc := range packageDateConfigs {
+		d, err := dc.compile()
+		if err != nil {
+			return err
+		}
+		detectors = append(detectors, d)
+	}
+	return runFilterWith("packages", detectors, args)
+}
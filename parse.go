@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// parseDescription details the parse command for its usage
+const parseDescription = `PHRASE is relative ("2 hours ago", "in 3 days") or natural ("next tuesday
+9am"), anchored on now or --now. Piped output is the epoch.`
+
+// runParseCommand turns a relative phrase into an epoch
+func runParseCommand(args []string) error {
+	fs := newFlagSet("parse")
+	out := addOutputFlags(fs, 1)
+	positional, err := parseArgs("parse", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return errors.New("parse requires a phrase such as \"2 hours ago\"")
+	}
+	input := strings.Join(positional, " ")
+
+	anchor := clock.Now()
+	t, err := timeago.ParseTime(translateInput(input), anchor)
+	if err != nil {
+		if _, derr := timeago.ParseDuration(translateInput(input)); derr == nil {
+			return fmt.Errorf("%q has no direction, write \"%s ago\" or \"in %s\"", input, input, input)
+		}
+		return errors.New(describeParseError(err))
+	}
+
+	epochMs := t.UnixMilli()
+	if !isTTY() {
+		fmt.Println(emitEpoch(epochMs))
+		return nil
+	}
+	fmt.Printf("Epoch: %d\n", epochMs)
+	fmt.Printf("UTC: %s\n", formatDateTime(t, true))
+	fmt.Printf("Local: %s\n", formatDateTime(t, false))
+	fmt.Printf("Time ago: %s\n", timeAgoAt(epochMs, out.precision, anchor))
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// pidDescription details the pid command for its usage
+const pidDescription = `Reads the start time of process PID from /proc on Linux or sysctl on
+macOS and reports how long it has been running. Piped output is the start
+epoch.`
+
+// runPidCommand reports the start time and uptime of a process
+func runPidCommand(args []string) error {
+	fs := newFlagSet("pid")
+	out := addOutputFlags(fs, 2)
+	positional, err := parseArgs("pid", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("pid requires one process ID")
+	}
+	pid, err := strconv.Atoi(positional[0])
+	if err != nil || pid < 1 {
+		return fmt.Errorf("invalid process ID %q", positional[0])
+	}
+
+	start, name, err := processStart(pid)
+	if err != nil {
+		return err
+	}
+	if !isTTY() {
+		fmt.Println(emitEpoch(start.UnixMilli()))
+		return nil
+	}
+	fmt.Printf("PID: %d (%s)\n", pid, name)
+	fmt.Printf("Started: %s (%s)\n", formatDateTime(start, false), timeAgo(start.UnixMilli(), out.precision))
+	fmt.Printf("Running for: %s\n", normalizeDuration(clock.Now().Sub(start), out.precision))
+	return nil
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// processStart returns the start time and command name of a process
+func processStart(pid int) (time.Time, string, error) {
+	info, err := unix.SysctlKinfoProc("kern.proc.pid", pid)
+	if err != nil || info.Proc.P_pid != int32(pid) {
+		return time.Time{}, "", fmt.Errorf("no process with PID %d", pid)
+	}
+	start := info.Proc.P_starttime
+	return time.Unix(start.Unix()), unix.ByteSliceToString(info.Proc.P_comm[:]), nil
+}
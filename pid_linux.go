@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// userHZ is the unit of the start time in /proc/PID/stat, fixed at 100
+// ticks per second by the kernel ABI
+const userHZ = 100
+
+// bootTime reads the boot time from the btime line of /proc/stat
+func bootTime() (time.Time, error) {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rest, ok := strings.CutPrefix(scanner.Text(), "btime "); ok {
+			sec, err := strconv.ParseInt(strings.TrimSpace(rest), 10, 64)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("invalid btime in /proc/stat: %q", rest)
+			}
+			return time.Unix(sec, 0), nil
+		}
+	}
+	return time.Time{}, errors.New("no btime in /proc/stat")
+}
+
+// processStart returns the start time and command name of a process
+func processStart(pid int) (time.Time, string, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if errors.Is(err, os.ErrNotExist) {
+		return time.Time{}, "", fmt.Errorf("no process with PID %d", pid)
+	}
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	// The command name is parenthesized and may hold spaces or parentheses
+	stat := string(data)
+	open, end := strings.IndexByte(stat, '('), strings.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return time.Time{}, "", fmt.Errorf("unexpected /proc/%d/stat format", pid)
+	}
+	name := stat[open+1 : end]
+	// Fields after the name start at the state, field 3; starttime is 22
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 20 {
+		return time.Time{}, "", fmt.Errorf("unexpected /proc/%d/stat format", pid)
+	}
+	ticks, err := strconv.ParseInt(fields[19], 10, 64)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("unexpected /proc/%d/stat format", pid)
+	}
+
+	boot, err := bootTime()
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	return boot.Add(time.Duration(ticks) * time.Second / userHZ), name, nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package main
+
+import (
+	"errors"
+	"time"
+)
+
+// processStart is only implemented for Linux and macOS
+func processStart(pid int) (time.Time, string, error) {
+	return time.Time{}, "", errors.New("process start times are not supported on this system")
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// pomodoroOptions configures the pomodoro cycle runner
+type pomodoroOptions struct {
+	work   time.Duration
+	rest   time.Duration
+	cycles int
+	hook   string // shell command run at the start of every phase
+}
+
+// runPomodoroCommand reads the pomodoro flags and runs the cycles
+func runPomodoroCommand(args []string) error {
+	opts := pomodoroOptions{work: 25 * time.Minute, rest: 5 * time.Minute}
+	fs := newFlagSet("pomodoro")
+	fs.Var(durationValue{&opts.work}, "work", "length of a work phase")
+	fs.Var(durationValue{&opts.rest}, "break", "length of a break")
+	fs.IntVar(&opts.cycles, "cycles", 4, "number of work phases")
+	fs.StringVar(&opts.hook, "exec", "", "shell command run at the start of every phase")
+	if _, err := parseArgs("pomodoro", fs, args); err != nil {
+		return err
+	}
+	if opts.work <= 0 || opts.rest <= 0 {
+		return fmt.Errorf("--work and --break must be positive")
+	}
+	if opts.cycles < 1 {
+		return fmt.Errorf("--cycles requires a positive number")
+	}
+	return runPomodoro(opts, isTTY())
+}
+
+// runPomodoro alternates work and break phases, notifying at each change.
+// The hook receives TIMEAGO_PHASE (work, break or done) and TIMEAGO_CYCLE.
+func runPomodoro(opts pomodoroOptions, isTTY bool) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	phase := func(name string, cycle int, d time.Duration) error {
+		if err := runHook(opts.hook, "TIMEAGO_PHASE="+name, "TIMEAGO_CYCLE="+strconv.Itoa(cycle)); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: hook failed: %s\n", err)
+		}
+		if !isTTY {
+			fmt.Printf("%s %d/%d %s\n", name, cycle, opts.cycles, formatClock(d))
+		}
+		label := fmt.Sprintf("%s %d/%d", name, cycle, opts.cycles)
+		return waitUntil(ctx, time.Now().Add(d), label, isTTY)
+	}
+
+	for cycle := 1; cycle <= opts.cycles; cycle++ {
+		if err := phase("work", cycle, opts.work); err != nil {
+			return err
+		}
+		if cycle == opts.cycles {
+			break
+		}
+		notify("timeago pomodoro", fmt.Sprintf("Cycle %d done, take a %s break", cycle, timeago.NewFormatter().Duration(opts.rest)), isTTY)
+		if err := phase("break", cycle, opts.rest); err != nil {
+			return err
+		}
+		notify("timeago pomodoro", "Break over, back to work", isTTY)
+	}
+
+	notify("timeago pomodoro", fmt.Sprintf("All %d cycles done", opts.cycles), isTTY)
+	if err := runHook(opts.hook, "TIMEAGO_PHASE=done", "TIMEAGO_CYCLE="+strconv.Itoa(opts.cycles)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: hook failed: %s\n", err)
+	}
+	if isTTY {
+		fmt.Printf("Done: %d cycles of %s\n", opts.cycles, timeago.NewFormatter().Duration(opts.work))
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// progressReader counts the bytes read through it for --progress
+type progressReader struct {
+	r io.Reader
+	n atomic.Int64
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.n.Add(int64(n))
+	return n, err
+}
+
+// formatBytes renders a size in binary units, e.g. "1.5 GiB"
+func formatBytes(n int64) string {
+	const units = "KMGTPE"
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, i := float64(n)/1024, 0
+	for value >= 1024 && i < len(units)-1 {
+		value /= 1024
+		i++
+	}
+	return fmt.Sprintf("%.1f %ciB", value, units[i])
+}
+
+// withProgress wraps r and reports the bytes read, the throughput and, for
+// regular files, the share done and time left on stderr until stop is
+// called. A terminal gets one line redrawn twice a second, anything else a
+// line every ten seconds.
+func withProgress(r io.Reader) (io.Reader, func()) {
+	p := &progressReader{r: r}
+	var total int64
+	if f, ok := r.(*os.File); ok {
+		if info, err := f.Stat(); err == nil && info.Mode().IsRegular() {
+			total = info.Size()
+		}
+	}
+	tty := term.IsTerminal(int(os.Stderr.Fd()))
+	interval, eol := 10*time.Second, "\n"
+	if tty {
+		interval, eol = 500*time.Millisecond, ""
+	}
+
+	start := time.Now()
+	report := func() {
+		n := p.n.Load()
+		elapsed := time.Since(start)
+		rate := float64(n) / max(elapsed.Seconds(), 0.001)
+		status := formatBytes(n)
+		if total > 0 {
+			status += fmt.Sprintf(" of %s (%.0f%%)", formatBytes(total), 100*float64(n)/float64(total))
+		}
+		status += fmt.Sprintf(", %s/s", formatBytes(int64(rate)))
+		if left := time.Duration(float64(total-n) / max(rate, 1) * float64(time.Second)); total > n && left >= time.Second {
+			status += ", " + newFormatter(1).Duration(left) + " left"
+		}
+		if tty {
+			// Clear the rest of the previous, possibly longer, line
+			status = "\r" + status + "\033[K"
+		}
+		fmt.Fprint(os.Stderr, status+eol)
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				report()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	stop := func() {
+		close(done)
+		wg.Wait()
+		report()
+		if tty {
+			fmt.Fprintln(os.Stderr)
+		}
+	}
+	return p, stop
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+	"golang.org/x/term"
+)
+
+// promptAmbiguous enables asking which reading of an ambiguous timestamp
+// is meant; commands with output flags turn it on unless --no-prompt
+var promptAmbiguous = false
+
+// reading is one interpretation of an ambiguous timestamp
+type reading struct {
+	label   string
+	epochMs int64
+}
+
+// canPrompt reports whether a question can be asked and answered
+func canPrompt() bool {
+	return promptAmbiguous && term.IsTerminal(int(os.Stdin.Fd())) && isTTY()
+}
+
+// chooseReading asks on the terminal which reading of input is meant
+func chooseReading(input string, readings []reading) (int64, error) {
+	fmt.Fprintf(os.Stderr, "%q is ambiguous:\n", input)
+	for i, r := range readings {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, r.label)
+	}
+	fmt.Fprintf(os.Stderr, "Choose [1-%d]: ", len(readings))
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && answer == "" {
+		return 0, errors.New("no reading chosen")
+	}
+	choice, err := strconv.Atoi(strings.TrimSpace(answer))
+	if err != nil || choice < 1 || choice > len(readings) {
+		return 0, fmt.Errorf("invalid choice %q", strings.TrimSpace(answer))
+	}
+	return readings[choice-1].epochMs, nil
+}
+
+// confirm asks a yes/no question on the terminal; anything but y or yes
+// is a no
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// ambiguousEpoch reports whether an integer read by magnitude could as well
+// be in another unit: ten digits are seconds since 2001, or milliseconds of
+// early 1970
+func ambiguousEpoch(n int64) bool {
+	magnitude := epochMagnitude(n)
+	return epochUnit == "auto" && epochBase == nil && outputStyle != "git" && magnitude >= 1e9 && magnitude < 1e10
+}
+
+// epochReadings are the seconds and milliseconds readings of n
+func epochReadings(n int64) []reading {
+	return []reading{
+		{"seconds: " + formatDateTime(time.UnixMilli(n*1000), false), n * 1000},
+		{"milliseconds: " + formatDateTime(time.UnixMilli(n), false), n},
+	}
+}
+
+// dateReadings are the candidates of an ambiguous date
+func dateReadings(err *timeago.ErrAmbiguousDate) []reading {
+	readings := make([]reading, len(err.Candidates))
+	for i, t := range err.Candidates {
+		readings[i] = reading{t.Format("2006-01-02 (Monday, January 2)"), t.UnixMilli()}
+	}
+	return readings
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// rangeDescription details the range command in its usage
+const rangeDescription = `Phrases: today, yesterday, tomorrow, 2024-03-01,
+this|last|next day|week|month|quarter|year, last|next N days|weeks|...,
+Q1 2024, 2024, 2024-03, march 2024, and "<start> to <end>" such as
+"yesterday 9am to 5pm". The end is exclusive. Piped output is "START END".`
+
+// resolveRange parses the words of a range phrase relative to now in loc
+func resolveRange(words []string, loc *time.Location, command string) (timeago.Range, error) {
+	if len(words) == 0 {
+		return timeago.Range{}, fmt.Errorf("%s requires a range (e.g. \"last week\")", command)
+	}
+	r, err := timeago.ParseRange(strings.Join(words, " "), clock.Now().In(loc))
+	if err != nil {
+		return timeago.Range{}, errors.New(describeParseError(err))
+	}
+	return r, nil
+}
+
+// runRangeCommand prints the start and end epochs of a natural-language
+// range. Piped output is "START END" so scripts can `read start end`.
+func runRangeCommand(args []string) error {
+	loc := time.Local
+	fs := newFlagSet("range")
+	fs.Var(locationValue{&loc}, "tz", "time zone of the calendar")
+	out := addOutputFlags(fs, 7)
+	words, err := parseArgs("range", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	r, err := resolveRange(words, loc, "range")
+	if err != nil {
+		return err
+	}
+
+	printRange(r, loc, out.precision)
+	return nil
+}
+
+// printRange prints the bounds of r, as "START END" when piped
+func printRange(r timeago.Range, loc *time.Location, precision int) {
+	if !isTTY() {
+		fmt.Printf("%d %d\n", emitEpoch(r.Start.UnixMilli()), emitEpoch(r.End.UnixMilli()))
+		return
+	}
+
+	fmt.Printf("Start: %d\n", r.Start.UnixMilli())
+	fmt.Printf("Start UTC: %s\n", formatDateTime(r.Start, true))
+	fmt.Printf("Start %s: %s\n", loc, r.Start.In(loc).Format("2006-01-02 15:04:05"))
+	fmt.Printf("End: %d (exclusive)\n", r.End.UnixMilli())
+	fmt.Printf("End UTC: %s\n", formatDateTime(r.End, true))
+	fmt.Printf("End %s: %s\n", loc, r.End.In(loc).Format("2006-01-02 15:04:05"))
+	fmt.Printf("Duration: %s\n", newFormatter(precision).Duration(r.End.Sub(r.Start)))
+}
+
+// dayDescription details the day command in its usage
+const dayDescription = `DATE is YYYY-MM-DD, a phrase such as "yesterday" or any timestamp
+(default: today), read in --tz. The bounds are the local midnights, so the
+day lasts 23 or 25 hours across DST changes. Piped output is "START END".`
+
+// parseDayIn reads the day of input in loc: a YYYY-MM-DD date, an ISO 8601
+// timestamp (in loc unless it has an offset), a phrase resolved against the
+// current time in loc, or any other timestamp
+func parseDayIn(input string, loc *time.Location) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", "2006-01-02T15:04", "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, input, loc); err == nil {
+			return t, nil
+		}
+	}
+	if isoTimestamp.MatchString(input) {
+		if t, err := time.ParseInLocation(isoLayout(input), input, loc); err == nil {
+			return t.In(loc), nil
+		}
+	}
+	if t, err := timeago.ParseTime(input, clock.Now().In(loc)); err == nil {
+		return t, nil
+	}
+	epochMs, err := parseEpoch(input)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid date %q", input)
+	}
+	return time.UnixMilli(epochMs).In(loc), nil
+}
+
+// runDayCommand prints the exact start and end instants of a local
+// calendar day
+func runDayCommand(args []string) error {
+	loc := time.Local
+	fs := newFlagSet("day")
+	fs.Var(locationValue{&loc}, "tz", "time zone of the calendar")
+	out := addOutputFlags(fs, 7)
+	words, err := parseArgs("day", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+
+	day := clock.Now().In(loc)
+	if len(words) > 0 {
+		if day, err = parseDayIn(strings.Join(words, " "), loc); err != nil {
+			return err
+		}
+	}
+	r, _ := timeago.Period("day", day, 0)
+	printRange(r, loc, out.precision)
+	return nil
+}
+
+// runPeriodCommand prints the bounds of the current calendar period, or of
+// one offset from it
+func runPeriodCommand(args []string) error {
+	loc := time.Local
+	fs := newFlagSet("period")
+	fs.Var(locationValue{&loc}, "tz", "time zone of the calendar")
+	out := addOutputFlags(fs, 7)
+	offset := fs.Int("offset", 0, "periods to shift by, e.g. -1 for the previous one")
+	positional, err := parseArgs("period", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("period requires one of day, week, month, quarter or year")
+	}
+	r, err := timeago.Period(strings.ToLower(positional[0]), clock.Now().In(loc), *offset)
+	if err != nil {
+		return fmt.Errorf("unknown period %q (expected day, week, month, quarter or year)", positional[0])
+	}
+	printRange(r, loc, out.precision)
+	return nil
+}
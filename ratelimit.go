@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rateWindowDescription details the window command for its usage
+const rateWindowDescription = `Fixed windows of --per start at --since (default: the Unix epoch, so 1h
+windows reset on the hour in UTC). Reports when the current window resets,
+the requests left and the pace that spends them evenly until the reset.
+Exits with status 2 once --used reaches --every. Piped output is
+"RESET REMAINING".`
+
+// paceUnits are the units a pace is expressed in, smallest first
+var paceUnits = []struct {
+	name string
+	d    time.Duration
+}{{"second", time.Second}, {"minute", time.Minute}, {"hour", time.Hour}, {"day", 24 * time.Hour}}
+
+// describePace renders n requests spread over d, e.g. "2.74 per minute
+// (one every 22 seconds)", in the smallest unit allowing one request
+func describePace(n int, d time.Duration, f func(time.Duration) string) string {
+	unit := paceUnits[len(paceUnits)-1]
+	for _, u := range paceUnits {
+		if float64(n)*float64(u.d) >= float64(d) {
+			unit = u
+			break
+		}
+	}
+	rate := strconv.FormatFloat(float64(n)*float64(unit.d)/float64(d), 'f', 2, 64)
+	pace := fmt.Sprintf("%s per %s", strings.TrimRight(strings.TrimRight(rate, "0"), "."), unit.name)
+	if interval := d / time.Duration(n); interval >= time.Second {
+		pace += fmt.Sprintf(" (one every %s)", f(interval))
+	}
+	return pace
+}
+
+// runRateWindowCommand reports the state of a fixed rate-limit window
+func runRateWindowCommand(args []string) error {
+	fs := newFlagSet("window")
+	out := addOutputFlags(fs, 2)
+	every := fs.Int("every", 0, "requests allowed per window")
+	var per time.Duration
+	fs.Var(durationValue{&per}, "per", "window length, e.g. 1h")
+	used := fs.Int("used", 0, "requests already made in the current window")
+	since := fs.String("since", "", "start of any window (default: the Unix epoch)")
+	positional, err := parseArgs("window", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return errors.New("window takes no arguments")
+	}
+	if *every < 1 || per <= 0 {
+		return errors.New("window requires --every N and --per DURATION")
+	}
+	if *used < 0 {
+		return errors.New("--used cannot be negative")
+	}
+
+	now := clock.Now()
+	start := time.UnixMilli(0)
+	if *since != "" {
+		epochMs, err := parseEpoch(*since)
+		if err != nil {
+			return fmt.Errorf("Invalid timestamp %q", *since)
+		}
+		start = time.UnixMilli(epochMs)
+	}
+	if start.After(now) {
+		return fmt.Errorf("--since %s is in the future", formatDateTime(start, false))
+	}
+	windowStart := start.Add(now.Sub(start) / per * per)
+	reset := windowStart.Add(per)
+	remaining := max(*every-*used, 0)
+
+	if !isTTY() {
+		fmt.Printf("%d %d\n", emitEpoch(reset.UnixMilli()), remaining)
+	} else {
+		f := newFormatter(out.precision)
+		fmt.Printf("Window: %s to %s\n", formatDateTime(windowStart, false), formatDateTime(reset, false))
+		fmt.Printf("Resets: %s (%s)\n", formatDateTime(reset, false), timeAgo(reset.UnixMilli(), out.precision))
+		fmt.Printf("Used: %d of %d\n", *used, *every)
+		fmt.Printf("Remaining: %d\n", remaining)
+		if remaining > 0 {
+			fmt.Printf("Pace: %s\n", describePace(remaining, reset.Sub(now), f.Duration))
+		}
+	}
+	if remaining == 0 {
+		return exitStatus(2)
+	}
+	return nil
+}
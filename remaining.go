@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// remainingDescription details the remaining command for its usage
+const remainingDescription = `Exits with status 0 once TIMESTAMP is reached and 2 while time remains,
+for polling loops (until timeago remaining T --seconds; do sleep 10; done).
+Piped output is the remaining milliseconds, or seconds with --seconds (0 once passed).`
+
+// runRemainingCommand reports the time left until a timestamp
+func runRemainingCommand(args []string) error {
+	fs := newFlagSet("remaining")
+	out := addOutputFlags(fs, 2)
+	seconds := fs.Bool("seconds", false, "print only the integer seconds remaining")
+	positional, err := parseArgs("remaining", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return errors.New("remaining requires a timestamp")
+	}
+	input := strings.Join(positional, " ")
+	epochMs, err := parseEpoch(input)
+	if err != nil {
+		return fmt.Errorf("Invalid timestamp %q", input)
+	}
+
+	target := time.UnixMilli(epochMs)
+	left := max(target.Sub(clock.Now()), 0)
+
+	switch {
+	case *seconds:
+		// Round up so 0 is only printed once the timestamp has passed
+		fmt.Println(int64((left + time.Second - 1) / time.Second))
+	case isTTY():
+		fmt.Printf("Target: %s\n", formatDateTime(target.Local(), false))
+		if left == 0 {
+			fmt.Printf("Remaining: none (passed %s)\n", timeAgo(epochMs, out.precision))
+		} else {
+			fmt.Printf("Remaining: %s\n", newFormatter(out.precision).Duration(left))
+		}
+	default:
+		fmt.Println(left.Milliseconds())
+	}
+
+	if left > 0 {
+		return exitStatus(2)
+	}
+	return nil
+}
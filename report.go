@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// reportDescription details the report command for its usage
+const reportDescription = `Reads "START END [LABEL]" intervals or "TIMESTAMP [LABEL]" events from
+stdin, one per line, and prints a Markdown summary per period: the time
+and entries of each label and the busiest day. Entries count in the period
+of their start. Timestamps are epochs, ISO 8601 (read in --tz without an
+offset) or git ISO dates; phrases are not accepted, so labels stay labels.`
+
+// reportLabel totals one label within a period
+type reportLabel struct {
+	name    string
+	total   time.Duration
+	entries int
+}
+
+// reportPeriod collects the entries of one calendar period
+type reportPeriod struct {
+	r      timeago.Range
+	labels map[string]*reportLabel
+	days   map[time.Time]*reportLabel
+}
+
+// add counts one entry starting at start
+func (p *reportPeriod) add(label string, start time.Time, d time.Duration) {
+	if p.labels[label] == nil {
+		p.labels[label] = &reportLabel{name: label}
+	}
+	p.labels[label].total += d
+	p.labels[label].entries++
+
+	day, _ := timeago.Period("day", start, 0)
+	if p.days[day.Start] == nil {
+		p.days[day.Start] = &reportLabel{}
+	}
+	p.days[day.Start].total += d
+	p.days[day.Start].entries++
+}
+
+// reportTimestamp reads the timestamp starting fields: an epoch, an ISO
+// 8601 timestamp (offsetless ones in loc) or a git ISO date spanning three
+// fields ("2024-01-01 10:00:00 +0100"). It returns the epoch in ms and the
+// number of fields read. Phrases are not accepted, so a label is never
+// mistaken for a time.
+func reportTimestamp(fields []string, loc *time.Location) (int64, int, bool) {
+	if len(fields) >= 3 {
+		if t, err := time.Parse(gitISOLayout, strings.Join(fields[:3], " ")); err == nil {
+			return t.UnixMilli(), 3, true
+		}
+	}
+	for n := min(len(fields), 2); n > 0; n-- {
+		s := strings.Join(fields[:n], " ")
+		if isoTimestamp.MatchString(s) {
+			t, err := time.ParseInLocation(isoLayout(s), s, loc)
+			return t.UnixMilli(), n, err == nil
+		}
+	}
+	if len(fields) > 0 {
+		if epoch, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+			return epochToMs(epoch), 1, true
+		}
+	}
+	return 0, 0, false
+}
+
+// readReport groups the entries read from r by the period unit in loc
+func readReport(r io.Reader, unit string, loc *time.Location) ([]*reportPeriod, error) {
+	periods := map[time.Time]*reportPeriod{}
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+
+		startMs, n, ok := reportTimestamp(fields, loc)
+		if !ok {
+			return nil, fmt.Errorf("line %d: invalid timestamp %q (expected an epoch, ISO 8601 or a git ISO date)", lineNo, fields[0])
+		}
+		start := time.UnixMilli(startMs).In(loc)
+		var d time.Duration
+		rest := fields[n:]
+		if endMs, n, ok := reportTimestamp(rest, loc); ok {
+			if endMs < startMs {
+				return nil, fmt.Errorf("line %d: end is before start", lineNo)
+			}
+			d = time.Duration(endMs-startMs) * time.Millisecond
+			rest = rest[n:]
+		}
+		label := strings.Join(rest, " ")
+		if label == "" {
+			label = "(unlabeled)"
+		}
+
+		pr, _ := timeago.Period(unit, start, 0)
+		if periods[pr.Start] == nil {
+			periods[pr.Start] = &reportPeriod{r: pr, labels: map[string]*reportLabel{}, days: map[time.Time]*reportLabel{}}
+		}
+		periods[pr.Start].add(label, start, d)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sorted := make([]*reportPeriod, 0, len(periods))
+	for _, p := range periods {
+		sorted = append(sorted, p)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].r.Start.Before(sorted[j].r.Start)
+	})
+	return sorted, nil
+}
+
+// periodTitle names a period for its Markdown heading
+func periodTitle(unit string, r timeago.Range) string {
+	switch unit {
+	case "day":
+		return r.Start.Format("Monday 2006-01-02")
+	case "week":
+		return "Week of " + r.Start.Format("2006-01-02")
+	case "month":
+		return r.Start.Format("January 2006")
+	case "quarter":
+		return fmt.Sprintf("Q%d %d", (int(r.Start.Month())+2)/3, r.Start.Year())
+	}
+	return r.Start.Format("2006")
+}
+
+// reportTime renders a label's time, or "-" for events alone
+func reportTime(d time.Duration, f *timeago.Formatter) string {
+	if d == 0 {
+		return "-"
+	}
+	return f.Duration(d)
+}
+
+// writeReport renders the periods as Markdown
+func writeReport(w io.Writer, unit string, periods []*reportPeriod, f *timeago.Formatter) {
+	for i, p := range periods {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		labels := make([]*reportLabel, 0, len(p.labels))
+		var total reportLabel
+		for _, l := range p.labels {
+			labels = append(labels, l)
+			total.total += l.total
+			total.entries += l.entries
+		}
+		sort.Slice(labels, func(i, j int) bool {
+			if labels[i].total != labels[j].total {
+				return labels[i].total > labels[j].total
+			}
+			if labels[i].entries != labels[j].entries {
+				return labels[i].entries > labels[j].entries
+			}
+			return labels[i].name < labels[j].name
+		})
+
+		fmt.Fprintf(w, "## %s\n\n", periodTitle(unit, p.r))
+		fmt.Fprintln(w, "| Label | Time | Entries |")
+		fmt.Fprintln(w, "|-------|------|---------|")
+		for _, l := range labels {
+			fmt.Fprintf(w, "| %s | %s | %d |\n", strings.ReplaceAll(l.name, "|", `\|`), reportTime(l.total, f), l.entries)
+		}
+		fmt.Fprintf(w, "| **Total** | **%s** | **%d** |\n", reportTime(total.total, f), total.entries)
+
+		// The busiest day has the most time, or the most entries for events
+		var busiest time.Time
+		for day, d := range p.days {
+			b := p.days[busiest]
+			if b == nil || d.total > b.total || (d.total == b.total && d.entries > b.entries) ||
+				(d.total == b.total && d.entries == b.entries && day.Before(busiest)) {
+				busiest = day
+			}
+		}
+		if unit != "day" {
+			b := p.days[busiest]
+			amount := fmt.Sprintf("%d entries", b.entries)
+			if b.entries == 1 {
+				amount = "1 entry"
+			}
+			if b.total > 0 {
+				amount = f.Duration(b.total) + ", " + amount
+			}
+			fmt.Fprintf(w, "\nBusiest day: %s (%s)\n", busiest.Format("Monday 2006-01-02"), amount)
+		}
+	}
+}
+
+// runReportCommand summarizes piped intervals and events per period
+func runReportCommand(args []string) error {
+	fs := newFlagSet("report")
+	out := addOutputFlags(fs, 2)
+	fs.Bool("stdin", true, "read the entries from stdin (the only input)")
+	unit := fs.String("period", "week", "group by day, week, month, quarter or year")
+	loc := time.Local
+	fs.Var(locationValue{&loc}, "tz", "time zone of the calendar")
+	positional, err := parseArgs("report", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return errors.New("report reads stdin and takes no arguments")
+	}
+	if _, err := timeago.Period(*unit, clock.Now(), 0); err != nil {
+		return fmt.Errorf("unsupported period %q (supported: day, week, month, quarter, year)", *unit)
+	}
+
+	periods, err := readReport(os.Stdin, *unit, loc)
+	if err != nil {
+		return err
+	}
+	if len(periods) == 0 {
+		return errors.New("report found no entries on stdin")
+	}
+	writeReport(os.Stdout, *unit, periods, hoursFormatter(out.precision))
+	return nil
+}
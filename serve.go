@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// serveDescription details the serve command for its usage
+const serveDescription = `GET /?t=<TIMESTAMP>&precision=N returns a JSON conversion object and
+GET /healthz returns {"status":"ok"}. Each request is logged to stderr as
+one JSON line (--log-format text for key=value). --addr is repeatable;
+SIGINT or SIGTERM stops accepting connections and waits up to
+--shutdown-timeout for requests in flight.`
+
+// addrsValue is a repeatable flag.Value collecting listen addresses
+type addrsValue []string
+
+func (v *addrsValue) String() string {
+	return strings.Join(*v, ",")
+}
+
+func (v *addrsValue) Set(s string) error {
+	*v = append(*v, s)
+	return nil
+}
+
+// rateLimiter is a token bucket per client address, refilled at rate
+// tokens per second up to burst
+type rateLimiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	clients map[string]*tokenBucket
+	swept   time.Time
+}
+
+// tokenBucket is the state of one client
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter returns a limiter allowing rate requests per second per
+// client, with bursts of up to rate requests
+func newRateLimiter(rate float64) *rateLimiter {
+	return &rateLimiter{rate: rate, burst: max(rate, 1), clients: map[string]*tokenBucket{}}
+}
+
+// allow takes a token from the client's bucket
+func (l *rateLimiter) allow(client string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Buckets refilled to the brim are indistinguishable from new ones
+	if now.Sub(l.swept) > time.Minute {
+		for c, b := range l.clients {
+			if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+				delete(l.clients, c)
+			}
+		}
+		l.swept = now
+	}
+
+	b, ok := l.clients[client]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.clients[client] = b
+	}
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// statusRecorder captures the status and size of a response for the log
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += n
+	return n, err
+}
+
+// serveOptions are the limits and logging of serve
+type serveOptions struct {
+	maxBody int64
+	limiter *rateLimiter // nil when unlimited
+	logger  *slog.Logger
+}
+
+// wrap applies the body limit, the rate limit and request logging to next
+func (o serveOptions) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		client, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			client = r.RemoteAddr
+		}
+
+		r.Body = http.MaxBytesReader(rec, r.Body, o.maxBody)
+		// Health probes are never rate limited
+		if o.limiter != nil && r.URL.Path != "/healthz" && !o.limiter.allow(client, start) {
+			rec.Header().Set("Retry-After", "1")
+			http.Error(rec, "rate limit exceeded", http.StatusTooManyRequests)
+		} else {
+			next.ServeHTTP(rec, r)
+		}
+
+		o.logger.Info("request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"query", r.URL.RawQuery,
+			"status", rec.status,
+			"bytes", rec.bytes,
+			"duration_ms", float64(time.Since(start).Microseconds())/1000,
+			"client", client,
+		)
+	})
+}
+
+// healthHandler answers liveness probes
+func healthHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintln(w, `{"status":"ok"}`)
+}
+
+// runServeCommand parses the serve flags and listens until interrupted
+func runServeCommand(args []string) error {
+	fs := newFlagSet("serve")
+	var addrs addrsValue
+	fs.Var(&addrs, "addr", "HOST:PORT to listen on, repeatable (default 127.0.0.1:8080)")
+	logFormat := fs.String("log-format", "json", "request log format on stderr: json, text or none")
+	maxBody := fs.Int64("max-body", 1<<20, "largest request body accepted, in bytes")
+	rate := fs.Float64("rate", 0, "requests per second allowed per client, 0 for unlimited")
+	shutdownTimeout := 10 * time.Second
+	fs.Var(durationValue{&shutdownTimeout}, "shutdown-timeout", "how long to wait for requests in flight when stopping")
+	positional, err := parseArgs("serve", fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return errors.New("serve takes no arguments")
+	}
+	if len(addrs) == 0 {
+		addrs = addrsValue{"127.0.0.1:8080"}
+	}
+	if *maxBody < 0 || *rate < 0 {
+		return errors.New("--max-body and --rate cannot be negative")
+	}
+
+	opts := serveOptions{maxBody: *maxBody}
+	switch *logFormat {
+	case "json":
+		opts.logger = slog.New(slog.NewJSONHandler(os.Stderr, nil))
+	case "text":
+		opts.logger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	case "none":
+		opts.logger = slog.New(slog.DiscardHandler)
+	default:
+		return fmt.Errorf("unsupported --log-format %q (supported: json, text, none)", *logFormat)
+	}
+	if *rate > 0 {
+		opts.limiter = newRateLimiter(*rate)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return runServe(ctx, addrs, opts, shutdownTimeout)
+}
+
+// runServe exposes the library handler over HTTP on every address until
+// ctx is done, then shuts down gracefully
+func runServe(ctx context.Context, addrs []string, opts serveOptions, shutdownTimeout time.Duration) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", healthHandler)
+	mux.Handle("/", timeago.Handler("t"))
+	server := &http.Server{
+		Handler:           opts.wrap(timeago.Middleware(timeago.NewFormatter())(mux)),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+		MaxHeaderBytes:    64 << 10,
+	}
+
+	// Bind every address first, so a typo fails before anything is served
+	listeners := make([]net.Listener, 0, len(addrs))
+	for _, addr := range addrs {
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			for _, open := range listeners {
+				open.Close()
+			}
+			return err
+		}
+		listeners = append(listeners, l)
+	}
+
+	errs := make(chan error, len(listeners))
+	for _, l := range listeners {
+		opts.logger.Info("listening", "addr", "http://"+l.Addr().String())
+		go func() {
+			if err := server.Serve(l); !errors.Is(err, http.ErrServerClosed) {
+				errs <- err
+			}
+		}()
+	}
+
+	select {
+	case err := <-errs:
+		server.Close()
+		return err
+	case <-ctx.Done():
+	}
+	opts.logger.Info("shutting down", "timeout", shutdownTimeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// shellInitDescription details the shell-init command for its usage
+const shellInitDescription = `Prints hooks to evaluate in the shell's startup file:
+  eval "$(timeago shell-init zsh)"        # ~/.zshrc
+  eval "$(timeago shell-init bash)"       # ~/.bashrc, bash 5 or later
+  timeago shell-init fish | source        # ~/.config/fish/config.fish
+After each command, $TIMEAGO_LAST holds its humanized duration and
+$TIMEAGO_LAST_MS its milliseconds; commands lasting --min or more also
+print "took ..." on stderr. The bash hooks use the DEBUG trap.`
+
+// shellHooks are the hook scripts of shell-init; %[1]d is the --min
+// threshold in milliseconds and %[2]d the precision
+var shellHooks = map[string]string{
+	"zsh": `zmodload zsh/datetime
+_timeago_preexec() { _timeago_start=$EPOCHREALTIME }
+_timeago_precmd() {
+  [[ -n $_timeago_start ]] || return
+  typeset -gi TIMEAGO_LAST_MS=$(( (EPOCHREALTIME - _timeago_start) * 1000 ))
+  unset _timeago_start
+  typeset -g TIMEAGO_LAST="$(command timeago shell-init --report $TIMEAGO_LAST_MS -p %[2]d)"
+  (( TIMEAGO_LAST_MS >= %[1]d )) && print -u2 "took $TIMEAGO_LAST"
+}
+autoload -Uz add-zsh-hook
+add-zsh-hook preexec _timeago_preexec
+add-zsh-hook precmd _timeago_precmd
+`,
+	"bash": `_timeago_preexec() {
+  [[ -n $_timeago_start || $BASH_COMMAND == _timeago_precmd* ]] && return
+  _timeago_start=${EPOCHREALTIME/[.,]/}
+}
+_timeago_precmd() {
+  local _timeago_status=$?
+  if [[ -n $_timeago_start ]]; then
+    TIMEAGO_LAST_MS=$(( (${EPOCHREALTIME/[.,]/} - _timeago_start) / 1000 ))
+    unset _timeago_start
+    TIMEAGO_LAST=$(command timeago shell-init --report "$TIMEAGO_LAST_MS" -p %[2]d)
+    (( TIMEAGO_LAST_MS >= %[1]d )) && printf 'took %%s\n' "$TIMEAGO_LAST" >&2
+  fi
+  return $_timeago_status
+}
+trap _timeago_preexec DEBUG
+PROMPT_COMMAND="_timeago_precmd${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+`,
+	"fish": `function _timeago_postexec --on-event fish_postexec
+    set -g TIMEAGO_LAST_MS $CMD_DURATION
+    set -g TIMEAGO_LAST (command timeago shell-init --report $CMD_DURATION -p %[2]d)
+    if test $CMD_DURATION -ge %[1]d
+        echo "took $TIMEAGO_LAST" >&2
+    end
+end
+`,
+}
+
+// runShellInitCommand prints the hooks of a shell, or with --report the
+// humanized duration the hooks store in $TIMEAGO_LAST
+func runShellInitCommand(args []string) error {
+	fs := newFlagSet("shell-init")
+	out := addOutputFlags(fs, 2)
+	threshold := 5 * time.Second
+	fs.Var(durationValue{&threshold}, "min", "print \"took ...\" for commands lasting at least this long")
+	report := fs.String("report", "", "print these milliseconds humanized, as the hooks do")
+	positional, err := parseArgs("shell-init", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+
+	if *report != "" {
+		ms, err := strconv.ParseInt(*report, 10, 64)
+		if err != nil || ms < 0 {
+			return fmt.Errorf("--report requires milliseconds, got %q", *report)
+		}
+		fmt.Println(newFormatter(out.precision).Duration(time.Duration(ms) * time.Millisecond))
+		return nil
+	}
+
+	shells := make([]string, 0, len(shellHooks))
+	for shell := range shellHooks {
+		shells = append(shells, shell)
+	}
+	sort.Strings(shells)
+	if len(positional) != 1 {
+		return fmt.Errorf("shell-init requires a shell: %s", strings.Join(shells, ", "))
+	}
+	hooks, ok := shellHooks[positional[0]]
+	if !ok {
+		return fmt.Errorf("unsupported shell %q (supported: %s)", positional[0], strings.Join(shells, ", "))
+	}
+	fmt.Printf(hooks, threshold.Milliseconds(), out.precision)
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// parseOffset parses a duration with an optional leading sign (e.g. "-37d4h")
+func parseOffset(input string) (int64, error) {
+	input = strings.TrimSpace(input)
+	sign := int64(1)
+	if strings.HasPrefix(input, "-") {
+		sign = -1
+		input = input[1:]
+	} else {
+		input = strings.TrimPrefix(input, "+")
+	}
+
+	ms, err := parseTimeString(input)
+	if err != nil {
+		return 0, err
+	}
+	return sign * ms, nil
+}
+
+// shiftLine moves every detected timestamp by offsetMs, keeping its format
+func shiftLine(line string, matches []timestampMatch, offsetMs int64) string {
+	var out strings.Builder
+	last := 0
+	offset := time.Duration(offsetMs) * time.Millisecond
+	for _, m := range matches {
+		out.WriteString(line[last:m.start])
+		out.WriteString(m.detector.format(m.t.Add(offset), line[m.start:m.end]))
+		last = m.end
+	}
+	out.WriteString(line[last:])
+	return out.String()
+}
+
+// runShift copies r to w with every detected timestamp shifted by offsetMs.
+// Everything else is kept byte for byte, line endings included; binary lines
+// and lines longer than maxLineBytes are passed through untouched.
+func runShift(r io.Reader, w io.Writer, offsetMs int64, maxLineBytes int) error {
+	detectors, err := loadDetectors()
+	if err != nil {
+		return err
+	}
+
+	pass := func(out *bufio.Writer, b []byte) error {
+		_, err := out.Write(b)
+		return err
+	}
+	process := func(out *bufio.Writer, line []byte) error {
+		body, eol := splitEOL(line)
+		if len(body) > maxLineBytes || isBinary(body) {
+			return pass(out, line)
+		}
+		text := string(body)
+		if _, err := out.WriteString(shiftLine(text, findTimestamps(text, detectors), offsetMs)); err != nil {
+			return err
+		}
+		_, err := out.Write(eol)
+		return err
+	}
+	return scanLines(r, w, maxLineBytes, process, pass)
+}
+
+// runShiftCommand shifts the timestamps read from stdin by --by
+func runShiftCommand(args []string) error {
+	fs := newFlagSet("shift")
+	by := fs.String("by", "", "offset with an optional sign (e.g. -37d4h)")
+	fs.Bool("stdin", true, "read from stdin (the only input)")
+	maxLineBytes := fs.Int("max-line-bytes", 1024*1024, "pass longer lines through untouched")
+	progress := fs.Bool("progress", false, "report the bytes processed on stderr")
+	if _, err := parseArgs("shift", fs, args); err != nil {
+		return err
+	}
+	if *by == "" {
+		return fmt.Errorf("shift requires --by <TIME>")
+	}
+	if *maxLineBytes < 1 {
+		return fmt.Errorf("--max-line-bytes requires a positive number")
+	}
+	offsetMs, err := parseOffset(*by)
+	if err != nil {
+		return errors.New(describeParseError(err))
+	}
+
+	var input io.Reader = os.Stdin
+	if *progress {
+		var stop func()
+		input, stop = withProgress(input)
+		defer stop()
+	}
+	return runShift(input, os.Stdout, offsetMs, *maxLineBytes)
+}
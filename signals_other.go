@@ -0,0 +1,9 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// resizeSignals and reloadSignals have no equivalent outside Unix, where
+// live displays only redraw on their interval
+var resizeSignals, reloadSignals []os.Signal
@@ -0,0 +1,14 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// resizeSignals report terminal size changes to live displays
+var resizeSignals = []os.Signal{syscall.SIGWINCH}
+
+// reloadSignals ask live displays to re-read the config file
+var reloadSignals = []os.Signal{syscall.SIGUSR1}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// sqlDialect renders timestamp literals and identifiers for one database
+type sqlDialect struct {
+	literal func(t time.Time) string
+	quote   func(ident string) string
+}
+
+// sqlDialects lists the supported --dialect values. Literals are in UTC.
+var sqlDialects = map[string]sqlDialect{
+	"postgres": {
+		literal: func(t time.Time) string { return "'" + t.UTC().Format("2006-01-02 15:04:05.999-07:00") + "'" },
+		quote:   func(ident string) string { return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"` },
+	},
+	"mysql": {
+		literal: func(t time.Time) string { return "'" + t.UTC().Format("2006-01-02 15:04:05.999") + "'" },
+		quote:   func(ident string) string { return "`" + strings.ReplaceAll(ident, "`", "``") + "`" },
+	},
+	"sqlite": {
+		literal: func(t time.Time) string { return "'" + t.UTC().Format("2006-01-02 15:04:05.999") + "'" },
+		quote:   func(ident string) string { return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"` },
+	},
+	"bigquery": {
+		literal: func(t time.Time) string { return "TIMESTAMP '" + t.UTC().Format("2006-01-02 15:04:05.999-07:00") + "'" },
+		quote:   func(ident string) string { return "`" + strings.ReplaceAll(ident, "`", "\\`") + "`" },
+	},
+}
+
+// plainIdentifier matches column references that need no quoting
+var plainIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// sqlClause builds a half-open WHERE condition on column for r. A non-empty
+// epochUnit ("s" or "ms") compares against numeric epoch columns instead.
+func sqlClause(r timeago.Range, column string, dialect sqlDialect, epochUnit string) string {
+	if !plainIdentifier.MatchString(column) {
+		column = dialect.quote(column)
+	}
+
+	var start, end string
+	switch epochUnit {
+	case "s":
+		start, end = fmt.Sprint(r.Start.Unix()), fmt.Sprint(r.End.Unix())
+	case "ms":
+		start, end = fmt.Sprint(r.Start.UnixMilli()), fmt.Sprint(r.End.UnixMilli())
+	default:
+		start, end = dialect.literal(r.Start), dialect.literal(r.End)
+	}
+	return fmt.Sprintf("%s >= %s AND %s < %s", column, start, column, end)
+}
+
+// runSQLCommand prints a WHERE-clause snippet selecting a natural-language range
+func runSQLCommand(args []string) error {
+	loc := time.Local
+	fs := newFlagSet("sql")
+	column := fs.String("column", "created_at", "column to compare")
+	dialectName := fs.String("dialect", "postgres", "postgres, mysql, sqlite or bigquery")
+	epochUnit := fs.String("epoch", "", "compare numeric epoch columns: s or ms")
+	fs.Var(locationValue{&loc}, "tz", "time zone of the calendar")
+	var now string
+	fs.Var(nowValue{&now}, "now", nowUsage)
+	words, err := parseArgs("sql", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := setNow(now); err != nil {
+		return err
+	}
+
+	if *epochUnit != "" && *epochUnit != "s" && *epochUnit != "ms" {
+		return fmt.Errorf("--epoch must be s or ms")
+	}
+	dialect, ok := sqlDialects[strings.ToLower(*dialectName)]
+	if !ok {
+		return fmt.Errorf("unknown dialect %q (supported: postgres, mysql, sqlite, bigquery)", *dialectName)
+	}
+	r, err := resolveRange(words, loc, "sql")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(sqlClause(r, *column, dialect, *epochUnit))
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// templateData is the set of fields available to --template
+type templateData struct {
+	Input    string    // the timestamp as given
+	Epoch    int64     // epoch milliseconds
+	Seconds  int64     // epoch seconds
+	UTC      string    // absolute time in UTC, honoring --format and --round-to
+	Local    string    // absolute time in the local zone, likewise
+	ISO      string    // RFC 3339 in UTC
+	Relative string    // relative time in the selected style
+	Time     time.Time // for custom layouts: {{.Time.Format "Jan 2"}}
+}
+
+// newTemplateData computes the template fields of a timestamp
+func newTemplateData(input string, epochMs int64, precision int) templateData {
+	t := time.UnixMilli(epochMs)
+	return templateData{
+		Input:    input,
+		Epoch:    epochMs,
+		Seconds:  t.Unix(),
+		UTC:      formatDateTime(t, true),
+		Local:    formatDateTime(t, false),
+		ISO:      t.UTC().Format(time.RFC3339),
+		Relative: timeAgo(epochMs, precision),
+		Time:     t,
+	}
+}
+
+// parseOutputTemplate parses a --template and runs it once on sample data,
+// so misspelled fields fail before any input is read
+func parseOutputTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("--template").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := tmpl.Execute(io.Discard, newTemplateData("0", 0, 1)); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// templateFormat returns the per-line format rendering tmpl
+func templateFormat(tmpl *template.Template, precision int) convertLine {
+	return func(input string, epochMs int64) (string, error) {
+		var b strings.Builder
+		err := tmpl.Execute(&b, newTemplateData(input, epochMs, precision))
+		return b.String(), err
+	}
+}
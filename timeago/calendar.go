@@ -0,0 +1,94 @@
+package timeago
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CalendarDuration is a duration whose years, months and days follow the
+// calendar (month lengths, leap years, daylight saving changes) instead of
+// the fixed 365, 30 and 1 day approximations of ParseDuration
+type CalendarDuration struct {
+	Years  int
+	Months int
+	Days   int           // weeks are counted as 7 days
+	Clock  time.Duration // hours and smaller units
+}
+
+// ParseCalendarDuration parses a calendar duration with DefaultParser
+func ParseCalendarDuration(input string) (CalendarDuration, error) {
+	return DefaultParser.ParseCalendarDuration(input)
+}
+
+// ParseCalendarDuration parses the same syntax as ParseDuration, with the
+// same limits and errors, but keeps calendar units apart
+func (p *Parser) ParseCalendarDuration(input string) (CalendarDuration, error) {
+	if _, err := p.ParseDuration(input); err != nil {
+		return CalendarDuration{}, err
+	}
+
+	input = strings.TrimSpace(strings.TrimSuffix(normalizePhrase(input), "ago"))
+	input, _ = expandISODuration(input)
+	if val, err := strconv.ParseInt(input, 10, 64); err == nil {
+		return CalendarDuration{Clock: time.Duration(val) * time.Millisecond}, nil
+	}
+	if d, ok := goDuration(input); ok {
+		return CalendarDuration{Clock: d}, nil
+	}
+
+	var d CalendarDuration
+	for _, match := range durationTerms(input, p.Limits.MaxTokens) {
+		whole, fraction, _ := strings.Cut(match[0], ".")
+		value, _ := strconv.ParseInt(whole, 10, 64)
+		unit := durationUnits[strings.ToLower(match[1])]
+		switch unit {
+		case durationUnits["year"]:
+			d.Years += int(value)
+		case durationUnits["month"]:
+			d.Months += int(value)
+		case durationUnits["week"]:
+			d.Days += 7 * int(value)
+		case durationUnits["day"]:
+			d.Days += int(value)
+		default:
+			c, _ := termDuration(match[0], unit, p.Limits.MaxMagnitude)
+			d.Clock += c
+			continue
+		}
+		// The fraction of a calendar unit has its fixed length, so
+		// "1.5 days" is a day and 12 hours, "-1.5 days" minus both
+		sign := ""
+		if strings.HasPrefix(whole, "-") {
+			sign = "-"
+		}
+		c, _ := termDuration(sign+"0."+fraction+"0", unit, p.Limits.MaxMagnitude)
+		d.Clock += c
+	}
+	return d, nil
+}
+
+// addMonths moves t by whole months, clamping the day to the end of the
+// target month: January 31 plus one month is February 28 (or 29)
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	hour, min, sec := t.Clock()
+	return time.Date(first.Year(), first.Month(), day, hour, min, sec, t.Nanosecond(), t.Location())
+}
+
+// Add returns t moved forward by d, largest units first
+func (d CalendarDuration) Add(t time.Time) time.Time {
+	t = addMonths(t, d.Years*12+d.Months)
+	return t.AddDate(0, 0, d.Days).Add(d.Clock)
+}
+
+// Sub returns t moved back by d, smallest units first, so Sub undoes Add
+// except where Add clamped the day of the month
+func (d CalendarDuration) Sub(t time.Time) time.Time {
+	t = t.Add(-d.Clock).AddDate(0, 0, -d.Days)
+	return addMonths(t, -(d.Years*12 + d.Months))
+}
@@ -0,0 +1,35 @@
+package timeago
+
+import "time"
+
+// Clock supplies the current time. Formatter and Fuzzy read it for
+// Relative, so tests and reproducible output can pin "now" without
+// reaching for RelativeTo everywhere.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock reads the system clock
+type SystemClock struct{}
+
+// Now returns time.Now()
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock always returns the same instant, e.g.
+// FixedClock(time.UnixMilli(1700000000000))
+type FixedClock time.Time
+
+// Now returns the fixed instant
+func (c FixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// clockNow reads c, or the system clock when c is nil
+func clockNow(c Clock) time.Time {
+	if c == nil {
+		return time.Now()
+	}
+	return c.Now()
+}
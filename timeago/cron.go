@@ -0,0 +1,306 @@
+package timeago
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronField describes one of the five fields of a cron expression
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // three-letter names of the values, from min
+}
+
+// cronFields are minute, hour, day of month, month and day of week; a day
+// of week of 7 is Sunday, like 0
+var cronFields = [5]cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day-of-month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{name: "day-of-week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// cronMacros are the @ shorthands of cron
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronItem is one comma-separated element of a field, lo-hi/step; star
+// is "*" and ranged an explicit "a-b" or "a/step"
+type cronItem struct {
+	lo, hi, step int
+	star, ranged bool
+}
+
+// Cron is a parsed five-field cron expression
+type Cron struct {
+	items [5][]cronItem
+	sets  [5]uint64
+}
+
+// ParseCron parses a cron expression: five fields (minute, hour, day of
+// month, month, day of week) of values, names (jan, mon), ranges, lists
+// and steps, or a macro such as @daily. As in Vixie cron, a day matches
+// either day field when both are restricted.
+func ParseCron(expr string) (*Cron, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q needs 5 fields, got %d", expr, len(fields))
+	}
+
+	c := &Cron{}
+	for i, field := range fields {
+		f := cronFields[i]
+		for _, s := range strings.Split(field, ",") {
+			item, err := f.parseItem(s)
+			if err != nil {
+				return nil, err
+			}
+			c.items[i] = append(c.items[i], item)
+			for v := item.lo; v <= item.hi; v += item.step {
+				c.sets[i] |= 1 << v
+			}
+		}
+	}
+	if c.sets[4]&(1<<7) != 0 {
+		c.sets[4] = c.sets[4]&^(1<<7) | 1
+	}
+	return c, nil
+}
+
+// value reads a number or a name of the field
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("invalid %s %q (expected %d-%d)", f.name, s, f.min, f.max)
+	}
+	return n, nil
+}
+
+// parseItem reads "*", "a", "a-b", each optionally followed by "/step"
+func (f cronField) parseItem(s string) (cronItem, error) {
+	base, stepText, hasStep := strings.Cut(s, "/")
+	item := cronItem{lo: f.min, hi: f.max, step: 1}
+	if hasStep {
+		step, err := strconv.Atoi(stepText)
+		if err != nil || step < 1 || step > f.max {
+			return item, fmt.Errorf("invalid %s step %q", f.name, stepText)
+		}
+		item.step = step
+	}
+
+	lo, hi, isRange := strings.Cut(base, "-")
+	switch {
+	case base == "*":
+		item.star = true
+	case isRange:
+		var err error
+		if item.lo, err = f.value(lo); err != nil {
+			return item, err
+		}
+		if item.hi, err = f.value(hi); err != nil {
+			return item, err
+		}
+		if item.lo > item.hi {
+			return item, fmt.Errorf("invalid %s range %q", f.name, base)
+		}
+		item.ranged = true
+	default:
+		v, err := f.value(base)
+		if err != nil {
+			return item, err
+		}
+		item.lo = v
+		if hasStep {
+			item.ranged = true
+		} else {
+			item.hi = v
+		}
+	}
+	return item, nil
+}
+
+// starred reports whether field i starts with "*", which makes the day
+// fields combine with AND instead of OR
+func (c *Cron) starred(i int) bool {
+	return c.items[i][0].star
+}
+
+// every reports whether field i is a plain "*"
+func (c *Cron) every(i int) bool {
+	return len(c.items[i]) == 1 && c.items[i][0].star && c.items[i][0].step == 1
+}
+
+// dayMatches applies the day-of-month and day-of-week fields to t
+func (c *Cron) dayMatches(t time.Time) bool {
+	dom := c.sets[2]&(1<<t.Day()) != 0
+	dow := c.sets[4]&(1<<t.Weekday()) != 0
+	if c.starred(2) || c.starred(4) {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Next returns the first matching minute after t in t's location, or the
+// zero time when none comes within eight years (e.g. February 30)
+func (c *Cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	for limit := t.Year() + 8; t.Year() <= limit; {
+		switch {
+		case c.sets[3]&(1<<t.Month()) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case c.sets[1]&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case c.sets[0]&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// ordinal renders n as "2nd", "3rd", "11th"...
+func ordinal(n int) string {
+	suffix := "th"
+	switch n % 10 {
+	case 1:
+		suffix = "st"
+	case 2:
+		suffix = "nd"
+	case 3:
+		suffix = "rd"
+	}
+	if n%100 >= 11 && n%100 <= 13 {
+		suffix = "th"
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// joinList joins words as "a, b and c"
+func joinList(words []string) string {
+	if len(words) == 1 {
+		return words[0]
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " and " + words[len(words)-1]
+}
+
+// valueName renders a value of the field: "5", "March" or "Monday"
+func (f cronField) valueName(v int) string {
+	switch f.name {
+	case "month":
+		return time.Month(v).String()
+	case "day-of-week":
+		return time.Weekday(v % 7).String()
+	}
+	return strconv.Itoa(v)
+}
+
+// itemText renders an item, e.g. "minute 5", "Mondays", "every 2nd hour"
+// or "every day-of-week from Monday through Friday"
+func (f cronField) itemText(item cronItem) string {
+	every := "every " + f.name
+	if item.step > 1 {
+		every = "every " + ordinal(item.step) + " " + f.name
+	}
+	switch {
+	case item.star:
+		return every
+	case item.ranged:
+		return every + " from " + f.valueName(item.lo) + " through " + f.valueName(item.hi)
+	case f.name == "day-of-week":
+		return f.valueName(item.lo) + "s"
+	case f.name == "month":
+		return f.valueName(item.lo)
+	}
+	return f.name + " " + f.valueName(item.lo)
+}
+
+// fieldText renders field i; a list of numbers names the field once, as
+// in "minute 0 and 30"
+func (c *Cron) fieldText(i int) string {
+	f := cronFields[i]
+	if c.plain(i) && f.names == nil {
+		values := make([]string, len(c.items[i]))
+		for j, item := range c.items[i] {
+			values[j] = f.valueName(item.lo)
+		}
+		return f.name + " " + joinList(values)
+	}
+	texts := make([]string, len(c.items[i]))
+	for j, item := range c.items[i] {
+		texts[j] = f.itemText(item)
+	}
+	return joinList(texts)
+}
+
+// plain reports whether field i only lists single values
+func (c *Cron) plain(i int) bool {
+	for _, item := range c.items[i] {
+		if item.star || item.ranged {
+			return false
+		}
+	}
+	return true
+}
+
+// Describe renders the schedule in English, e.g. "At 03:00 on Mondays" or
+// "At every 15th minute past every hour from 9 through 17"
+func (c *Cron) Describe() string {
+	var b strings.Builder
+	if c.plain(0) && c.plain(1) && len(c.items[0])*len(c.items[1]) <= 4 {
+		var times []string
+		for _, hour := range c.items[1] {
+			for _, minute := range c.items[0] {
+				times = append(times, fmt.Sprintf("%02d:%02d", hour.lo, minute.lo))
+			}
+		}
+		b.WriteString("At " + joinList(times))
+	} else {
+		b.WriteString("At " + c.fieldText(0))
+		if !c.every(1) {
+			b.WriteString(" past " + c.fieldText(1))
+		}
+	}
+
+	if !c.every(2) {
+		b.WriteString(" on " + c.fieldText(2))
+	}
+	if !c.every(4) {
+		switch {
+		case c.every(2):
+			b.WriteString(" on ")
+		case c.starred(2) || c.starred(4):
+			b.WriteString(" and on ")
+		default:
+			b.WriteString(" or on ")
+		}
+		b.WriteString(c.fieldText(4))
+	}
+	if !c.every(3) {
+		b.WriteString(" in " + c.fieldText(3))
+	}
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package timeago
+
+import (
+	"fmt"
+	"time"
+)
+
+// CalendarDays returns the number of local midnights between t and now in
+// now's location: 0 on the same date, 1 when t was yesterday, -1 when it is
+// tomorrow, whatever the time of day or DST transitions in between
+func CalendarDays(t, now time.Time) int {
+	t = t.In(now.Location())
+	day := func(x time.Time) int64 {
+		return time.Date(x.Year(), x.Month(), x.Day(), 0, 0, 0, 0, time.UTC).Unix() / (24 * 60 * 60)
+	}
+	return int(day(now) - day(t))
+}
+
+// DailyRelative renders t relative to now in whole calendar days, ignoring
+// the time of day: "today", "yesterday", "3 days ago", "tomorrow" or
+// "in 3 days". Days change at midnight in now's location rather than every
+// 24 hours, so 23:00 yesterday is "yesterday" at 01:00.
+func DailyRelative(t, now time.Time) string {
+	switch days := CalendarDays(t, now); {
+	case days == 0:
+		return "today"
+	case days == 1:
+		return "yesterday"
+	case days == -1:
+		return "tomorrow"
+	case days < 0:
+		return fmt.Sprintf("in %d days", -days)
+	default:
+		return fmt.Sprintf("%d days ago", days)
+	}
+}
@@ -0,0 +1,51 @@
+package timeago
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidFormat is returned when an input does not match any known format
+type ErrInvalidFormat struct {
+	Input string
+}
+
+func (e *ErrInvalidFormat) Error() string {
+	return fmt.Sprintf("invalid time format: %s", e.Input)
+}
+
+// ErrUnknownUnit is returned when a duration uses an unrecognized unit
+type ErrUnknownUnit struct {
+	Unit string
+}
+
+func (e *ErrUnknownUnit) Error() string {
+	return fmt.Sprintf("unknown time unit: %s", e.Unit)
+}
+
+// ErrAmbiguousDate is returned when a date can be read several ways (e.g.
+// "03/04/2024" as March 4 or April 3). Candidates lists every reading.
+type ErrAmbiguousDate struct {
+	Input      string
+	Candidates []time.Time
+}
+
+func (e *ErrAmbiguousDate) Error() string {
+	readings := make([]string, len(e.Candidates))
+	for i, c := range e.Candidates {
+		readings[i] = c.Format("2006-01-02")
+	}
+	return fmt.Sprintf("ambiguous date %s: could be %s", e.Input, strings.Join(readings, " or "))
+}
+
+// ErrLimitExceeded is returned when an input exceeds one of the Parser's
+// Limits. Max is the configured bound (bytes, tokens or nanoseconds).
+type ErrLimitExceeded struct {
+	Limit string
+	Max   int64
+}
+
+func (e *ErrLimitExceeded) Error() string {
+	return fmt.Sprintf("input exceeds the %s limit", e.Limit)
+}
@@ -0,0 +1,211 @@
+// Package timeago converts timestamps and durations to human-readable text.
+//
+// Formatter and Fuzzy take a Clock for Relative. Parsing and calendar
+// arithmetic (ParseTime, ParseRange, CalendarDuration.Add and Sub) take
+// the reference instant as an argument instead: it is part of the input,
+// like the phrase itself, so one call with one value stays pure and a
+// caller holding a Clock passes its Now().
+package timeago
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Unit is one entry of the humanizer's unit table
+type Unit struct {
+	Singular string
+	Plural   string
+	Duration time.Duration
+	// Forms, when set, maps the CLDR plural categories returned by
+	// Formatter.PluralRule ("one", "few", "many", "other") to unit names
+	Forms map[string]string
+}
+
+// DefaultUnits is the unit table used by the CLI, largest unit first.
+// Months and years are fixed approximations (30 and 365 days).
+var DefaultUnits = []Unit{
+	{Singular: "year", Plural: "years", Duration: 365 * 24 * time.Hour},
+	{Singular: "month", Plural: "months", Duration: 30 * 24 * time.Hour},
+	{Singular: "week", Plural: "weeks", Duration: 7 * 24 * time.Hour},
+	{Singular: "day", Plural: "days", Duration: 24 * time.Hour},
+	{Singular: "hour", Plural: "hours", Duration: time.Hour},
+	{Singular: "minute", Plural: "minutes", Duration: time.Minute},
+	{Singular: "second", Plural: "seconds", Duration: time.Second},
+}
+
+// Formatter renders durations and instants with a configurable unit table,
+// thresholds and wording. The zero value is not usable, start from
+// NewFormatter and adjust the fields.
+type Formatter struct {
+	// Units lists the units to break durations into, largest first
+	Units []Unit
+	// Precision is the maximum number of units displayed
+	Precision int
+	// JustNow is the threshold under which differences render as JustNowText
+	JustNow time.Duration
+	// JustNowText is shown for differences below JustNow
+	JustNowText string
+	// PastFormat and FutureFormat wrap the duration text (e.g. "%s ago")
+	PastFormat   string
+	FutureFormat string
+	// Separator joins the units of a duration
+	Separator string
+	// LastSeparator, when set, joins the last two units instead of
+	// Separator (e.g. " and " for "2 hours and 30 minutes")
+	LastSeparator string
+	// Numbers renders unit counts; nil writes plain digits
+	Numbers func(int64) string
+	// PluralRule returns the CLDR plural category of a count, selecting
+	// among Unit.Forms; nil uses Singular for 1 and Plural otherwise
+	PluralRule func(int64) string
+	// UnitFormat places the count and the unit name (e.g. "%s%s" for
+	// languages written without a space); empty means "%s %s"
+	UnitFormat string
+	// Clock supplies now for Relative; nil reads the system clock
+	Clock Clock
+}
+
+// NewFormatter returns a Formatter with the CLI's default style
+func NewFormatter() *Formatter {
+	return &Formatter{
+		Units:        DefaultUnits,
+		Precision:    1,
+		JustNow:      time.Second,
+		JustNowText:  "just now",
+		PastFormat:   "%s ago",
+		FutureFormat: "in %s",
+		Separator:    " ",
+	}
+}
+
+// NewSpeechFormatter returns a Formatter suited to text-to-speech, with
+// spelled-out numbers and natural joins ("two hours and thirty minutes ago")
+func NewSpeechFormatter() *Formatter {
+	f := NewFormatter()
+	f.Separator = ", "
+	f.LastSeparator = " and "
+	f.Numbers = SpellNumber
+	return f
+}
+
+// ShortUnits is the unit table of NewShortFormatter
+var ShortUnits = []Unit{
+	{Singular: "y", Plural: "y", Duration: 365 * 24 * time.Hour},
+	{Singular: "mo", Plural: "mo", Duration: 30 * 24 * time.Hour},
+	{Singular: "w", Plural: "w", Duration: 7 * 24 * time.Hour},
+	{Singular: "d", Plural: "d", Duration: 24 * time.Hour},
+	{Singular: "h", Plural: "h", Duration: time.Hour},
+	{Singular: "m", Plural: "m", Duration: time.Minute},
+	{Singular: "s", Plural: "s", Duration: time.Second},
+}
+
+// NewShortFormatter returns a Formatter for compact, Twitter-style output
+// ("2h", "3d", "5mo"); past times carry no suffix and future ones read
+// "in 2h"
+func NewShortFormatter() *Formatter {
+	f := NewFormatter()
+	f.Units = ShortUnits
+	f.UnitFormat = "%s%s"
+	f.Separator = ""
+	f.JustNowText = "now"
+	f.PastFormat = "%s"
+	return f
+}
+
+// WithPrecision returns a copy of the formatter showing up to n units
+func (f Formatter) WithPrecision(n int) *Formatter {
+	f.Precision = n
+	return &f
+}
+
+// parts breaks a non-negative duration into at most Precision unit strings
+func (f *Formatter) parts(d time.Duration) []string {
+	var parts []string
+	remaining := d
+
+	for _, unit := range f.Units {
+		if remaining >= unit.Duration {
+			count := remaining / unit.Duration
+			remaining %= unit.Duration
+			parts = append(parts, f.unitText(int64(count), unit))
+
+			if len(parts) >= f.Precision {
+				break
+			}
+		}
+	}
+	return parts
+}
+
+// unitText renders a count of one unit, e.g. "2 hours"
+func (f *Formatter) unitText(count int64, unit Unit) string {
+	name := unit.Plural
+	if count == 1 {
+		name = unit.Singular
+	}
+	if f.PluralRule != nil && unit.Forms != nil {
+		if form, ok := unit.Forms[f.PluralRule(count)]; ok {
+			name = form
+		}
+	}
+
+	number := fmt.Sprintf("%d", count)
+	if f.Numbers != nil {
+		number = f.Numbers(count)
+	}
+	format := f.UnitFormat
+	if format == "" {
+		format = "%s %s"
+	}
+	return fmt.Sprintf(format, number, name)
+}
+
+// join assembles unit strings with Separator and LastSeparator
+func (f *Formatter) join(parts []string) string {
+	if f.LastSeparator == "" || len(parts) < 2 {
+		return strings.Join(parts, f.Separator)
+	}
+	last := len(parts) - 1
+	return strings.Join(parts[:last], f.Separator) + f.LastSeparator + parts[last]
+}
+
+// Duration renders the magnitude of d as text, e.g. "2 hours 30 minutes"
+func (f *Formatter) Duration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	parts := f.parts(d)
+	if len(parts) == 0 {
+		return f.unitText(0, f.Units[len(f.Units)-1])
+	}
+	return f.join(parts)
+}
+
+// Relative renders t relative to the formatter's Clock, e.g. "2 hours ago"
+// or "in 3 days"
+func (f *Formatter) Relative(t time.Time) string {
+	return f.RelativeTo(t, clockNow(f.Clock))
+}
+
+// RelativeTo renders t relative to the given instant instead of now
+func (f *Formatter) RelativeTo(t, now time.Time) string {
+	diff := now.Sub(t)
+
+	isFuture := diff < 0
+	if isFuture {
+		diff = -diff
+	}
+
+	parts := f.parts(diff)
+	if diff < f.JustNow || len(parts) == 0 {
+		return f.JustNowText
+	}
+
+	result := f.join(parts)
+	if isFuture {
+		return fmt.Sprintf(f.FutureFormat, result)
+	}
+	return fmt.Sprintf(f.PastFormat, result)
+}
@@ -0,0 +1,117 @@
+package timeago
+
+import (
+	"fmt"
+	"time"
+)
+
+// FuzzyUnits is the unit table of NewFuzzy; Singular carries the article
+// used for a count of one ("an hour")
+var FuzzyUnits = []Unit{
+	{Singular: "a year", Plural: "years", Duration: 365 * 24 * time.Hour},
+	{Singular: "a month", Plural: "months", Duration: 30 * 24 * time.Hour},
+	{Singular: "a week", Plural: "weeks", Duration: 7 * 24 * time.Hour},
+	{Singular: "a day", Plural: "days", Duration: 24 * time.Hour},
+	{Singular: "an hour", Plural: "hours", Duration: time.Hour},
+	{Singular: "a minute", Plural: "minutes", Duration: time.Minute},
+	{Singular: "a second", Plural: "seconds", Duration: time.Second},
+}
+
+// Fuzzy renders approximate relative times such as "about 2 hours ago",
+// "just over a week ago" or "almost a year ago". The thresholds are
+// fractions of a unit past a whole count:
+//
+//	[0, Over)        about N
+//	[Over, RoundUp)  just over N
+//	[RoundUp, Almost) about N+1
+//	[Almost, 1)      almost N+1
+//
+// A unit is used from Almost of it, so 0.9 years is "almost a year".
+type Fuzzy struct {
+	Units                 []Unit
+	Over, RoundUp, Almost float64
+	// JustNow is the threshold under which differences render as JustNowText
+	JustNow      time.Duration
+	JustNowText  string
+	PastFormat   string
+	FutureFormat string
+	// Clock supplies now for Relative; nil reads the system clock
+	Clock Clock
+}
+
+// NewFuzzy returns a Fuzzy with the default thresholds
+func NewFuzzy() *Fuzzy {
+	return &Fuzzy{
+		Units:        FuzzyUnits,
+		Over:         0.1,
+		RoundUp:      0.5,
+		Almost:       0.75,
+		JustNow:      time.Second,
+		JustNowText:  "just now",
+		PastFormat:   "%s ago",
+		FutureFormat: "in %s",
+	}
+}
+
+// Validate checks that the thresholds are ordered fractions of a unit
+func (f *Fuzzy) Validate() error {
+	if !(0 <= f.Over && f.Over <= f.RoundUp && f.RoundUp <= f.Almost && f.Almost <= 1) {
+		return fmt.Errorf("fuzzy thresholds must satisfy 0 <= over (%g) <= round_up (%g) <= almost (%g) <= 1",
+			f.Over, f.RoundUp, f.Almost)
+	}
+	return nil
+}
+
+// count renders n of unit, e.g. "an hour" or "3 hours"
+func (f *Fuzzy) count(n int64, unit Unit) string {
+	if n == 1 {
+		return unit.Singular
+	}
+	return fmt.Sprintf("%d %s", n, unit.Plural)
+}
+
+// Duration renders the magnitude of d approximately, e.g. "almost 2 hours"
+func (f *Fuzzy) Duration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	unit := f.Units[len(f.Units)-1]
+	for _, u := range f.Units {
+		if float64(d) >= f.Almost*float64(u.Duration) {
+			unit = u
+			break
+		}
+	}
+
+	x := float64(d) / float64(unit.Duration)
+	n := int64(x)
+	switch frac := x - float64(n); {
+	case n == 0 && frac < f.Almost:
+		// Below the smallest unit
+		return "less than " + unit.Singular
+	case frac < f.Over:
+		return "about " + f.count(n, unit)
+	case frac < f.RoundUp:
+		return "just over " + f.count(n, unit)
+	case frac < f.Almost:
+		return "about " + f.count(n+1, unit)
+	}
+	return "almost " + f.count(n+1, unit)
+}
+
+// Relative renders t relative to the Clock, e.g. "about 2 hours ago"
+func (f *Fuzzy) Relative(t time.Time) string {
+	return f.RelativeTo(t, clockNow(f.Clock))
+}
+
+// RelativeTo renders t relative to the given instant instead of now
+func (f *Fuzzy) RelativeTo(t, now time.Time) string {
+	diff := now.Sub(t)
+	if diff < f.JustNow && diff > -f.JustNow {
+		return f.JustNowText
+	}
+	if diff < 0 {
+		return fmt.Sprintf(f.FutureFormat, f.Duration(diff))
+	}
+	return fmt.Sprintf(f.PastFormat, f.Duration(diff))
+}
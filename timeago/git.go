@@ -0,0 +1,60 @@
+package timeago
+
+import (
+	"fmt"
+	"time"
+)
+
+// GitRelative renders t relative to now with the thresholds and rounding of
+// git log --date=relative, e.g. "3 weeks ago" or "1 year, 2 months ago".
+// Instants after now are "in the future", as in git.
+func GitRelative(t, now time.Time) string {
+	if t.After(now) {
+		return "in the future"
+	}
+	diff := int64(now.Sub(t) / time.Second)
+	if diff < 90 {
+		return gitAgo(diff, "second")
+	}
+	// Rounded minutes, hours and days, as in git's show_date_relative
+	diff = (diff + 30) / 60
+	if diff < 90 {
+		return gitAgo(diff, "minute")
+	}
+	diff = (diff + 30) / 60
+	if diff < 36 {
+		return gitAgo(diff, "hour")
+	}
+	diff = (diff + 12) / 24
+	if diff < 14 {
+		return gitAgo(diff, "day")
+	}
+	if diff < 70 {
+		return gitAgo((diff+3)/7, "week")
+	}
+	if diff < 365 {
+		return gitAgo((diff+15)/30, "month")
+	}
+	if diff < 1825 {
+		totalMonths := (diff*12*2 + 365) / (365 * 2)
+		years, months := totalMonths/12, totalMonths%12
+		if months == 0 {
+			return gitAgo(years, "year")
+		}
+		return fmt.Sprintf("%s, %s", gitCount(years, "year"), gitAgo(months, "month"))
+	}
+	return gitAgo((diff+183)/365, "year")
+}
+
+// gitAgo formats "n unit(s) ago"
+func gitAgo(n int64, unit string) string {
+	return gitCount(n, unit) + " ago"
+}
+
+// gitCount formats "n unit(s)" with git's singular for one
+func gitCount(n int64, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,100 @@
+package timeago
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"text/template"
+	"time"
+)
+
+// formatterKey is the context key holding the request's Formatter
+type formatterKey struct{}
+
+// WithFormatter returns a copy of ctx carrying f
+func WithFormatter(ctx context.Context, f *Formatter) context.Context {
+	return context.WithValue(ctx, formatterKey{}, f)
+}
+
+// FormatterFromContext returns the Formatter stored in ctx, or the default
+// one when none was set
+func FormatterFromContext(ctx context.Context) *Formatter {
+	if f, ok := ctx.Value(formatterKey{}).(*Formatter); ok {
+		return f
+	}
+	return NewFormatter()
+}
+
+// Middleware makes f available to downstream handlers through the request
+// context, so every handler of an application shares one house style
+func Middleware(f *Formatter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(WithFormatter(r.Context(), f)))
+		})
+	}
+}
+
+// Conversion is the JSON document returned by Handler
+type Conversion struct {
+	Epoch    int64  `json:"epoch"`
+	UTC      string `json:"utc"`
+	Relative string `json:"relative"`
+}
+
+// NewConversion describes t using f
+func NewConversion(t time.Time, f *Formatter) Conversion {
+	return Conversion{
+		Epoch:    t.UnixMilli(),
+		UTC:      t.UTC().Format(time.RFC3339),
+		Relative: f.Relative(t),
+	}
+}
+
+// Handler returns a handler humanizing the epoch milliseconds found in the
+// given query parameter, e.g. GET /?t=1700000000000&precision=2. The
+// Formatter comes from the request context (see Middleware).
+func Handler(param string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		epochMs, err := strconv.ParseInt(query.Get(param), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid epoch timestamp in parameter "+param, http.StatusBadRequest)
+			return
+		}
+
+		f := FormatterFromContext(r.Context())
+		if p := query.Get("precision"); p != "" {
+			n, err := strconv.Atoi(p)
+			if err != nil || n < 1 || n > len(f.Units) {
+				http.Error(w, "invalid precision", http.StatusBadRequest)
+				return
+			}
+			f = f.WithPrecision(n)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(NewConversion(time.UnixMilli(epochMs), f))
+	})
+}
+
+// FuncMap returns template functions backed by f: "timeago" renders a
+// time.Time or epoch milliseconds relative to now, "duration" renders a
+// time.Duration. It works with both text/template and html/template.
+func FuncMap(f *Formatter) template.FuncMap {
+	return template.FuncMap{
+		"timeago": func(v any) string {
+			switch t := v.(type) {
+			case time.Time:
+				return f.Relative(t)
+			case int64:
+				return f.Relative(time.UnixMilli(t))
+			case int:
+				return f.Relative(time.UnixMilli(int64(t)))
+			}
+			return ""
+		},
+		"duration": f.Duration,
+	}
+}
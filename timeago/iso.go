@@ -0,0 +1,35 @@
+package timeago
+
+import (
+	"regexp"
+	"strings"
+)
+
+// isoDuration matches ISO 8601 durations such as P1DT2H30M or PT1.5S
+var isoDuration = regexp.MustCompile(`(?i)^P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)(?:[.,](\d+))?S)?)?$`)
+
+// isoUnits names the fields of isoDuration in order
+var isoUnits = []string{"years", "months", "weeks", "days", "hours", "minutes", "seconds"}
+
+// expandISODuration rewrites an ISO 8601 duration into the terms of
+// ParseDuration ("P1DT2H" to "1 days 2 hours"), reporting whether input was
+// one. Fractions of a second are kept to the millisecond.
+func expandISODuration(input string) (string, bool) {
+	m := isoDuration.FindStringSubmatch(input)
+	if m == nil || strings.HasSuffix(strings.ToUpper(input), "T") {
+		return input, false
+	}
+	var terms []string
+	for i, unit := range isoUnits {
+		if m[i+1] != "" {
+			terms = append(terms, m[i+1]+" "+unit)
+		}
+	}
+	if m[8] != "" {
+		terms = append(terms, (m[8] + "00")[:3]+" milliseconds")
+	}
+	if len(terms) == 0 {
+		return input, false
+	}
+	return strings.Join(terms, " "), true
+}
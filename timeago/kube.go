@@ -0,0 +1,54 @@
+package timeago
+
+import (
+	"fmt"
+	"time"
+)
+
+// KubeAge renders d like the AGE column of kubectl: at most two units and
+// no spaces, e.g. "45s", "5m30s", "5d17h" or "3y120d". Durations more than
+// a second negative are "<invalid>", as in kubectl.
+func KubeAge(d time.Duration) string {
+	// kubectl tolerates a second of clock drift before calling a time invalid
+	if seconds := int64(d.Seconds()); seconds < -1 {
+		return "<invalid>"
+	} else if seconds < 0 {
+		return "0s"
+	} else if seconds < 60*2 {
+		return fmt.Sprintf("%ds", seconds)
+	}
+
+	minutes := int64(d / time.Minute)
+	if minutes < 10 {
+		if s := int64(d/time.Second) % 60; s != 0 {
+			return fmt.Sprintf("%dm%ds", minutes, s)
+		}
+		return fmt.Sprintf("%dm", minutes)
+	} else if minutes < 60*3 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+
+	hours := int64(d / time.Hour)
+	switch {
+	case hours < 8:
+		if m := minutes % 60; m != 0 {
+			return fmt.Sprintf("%dh%dm", hours, m)
+		}
+		return fmt.Sprintf("%dh", hours)
+	case hours < 48:
+		return fmt.Sprintf("%dh", hours)
+	case hours < 24*8:
+		if h := hours % 24; h != 0 {
+			return fmt.Sprintf("%dd%dh", hours/24, h)
+		}
+		return fmt.Sprintf("%dd", hours/24)
+	case hours < 24*365*2:
+		return fmt.Sprintf("%dd", hours/24)
+	case hours < 24*365*8:
+		if days := hours / 24 % 365; days != 0 {
+			return fmt.Sprintf("%dy%dd", hours/24/365, days)
+		}
+		return fmt.Sprintf("%dy", hours/24/365)
+	}
+	return fmt.Sprintf("%dy", hours/24/365)
+}
@@ -0,0 +1,221 @@
+package timeago
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Locale is the wording of relative times in one language
+type Locale struct {
+	// Units uses Forms keyed by the categories of PluralRule
+	Units        []Unit
+	PluralRule   func(int64) string
+	UnitFormat   string
+	JustNowText  string
+	PastFormat   string
+	FutureFormat string
+	Separator    string
+	// Aliases are further input spellings of units, mapped to the English
+	// singular (e.g. the nominative "tage" to "day"), for Translate
+	Aliases map[string]string
+}
+
+// CLDR plural rules for integer counts
+var (
+	// pluralOneOther: English, German, Spanish, Italian, Dutch
+	pluralOneOther = func(n int64) string {
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	}
+	// pluralZeroOne: French and Portuguese, where 0 is singular too
+	pluralZeroOne = func(n int64) string {
+		if n == 0 || n == 1 {
+			return "one"
+		}
+		return "other"
+	}
+	// pluralSlavic: Russian, with one/few/many by the last two digits
+	pluralSlavic = func(n int64) string {
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		}
+		return "many"
+	}
+	// pluralPolish: like Russian, but only 1 itself is "one"
+	pluralPolish = func(n int64) string {
+		switch {
+		case n == 1:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		}
+		return "many"
+	}
+	// pluralOther: Japanese and Chinese do not inflect for number
+	pluralOther = func(int64) string {
+		return "other"
+	}
+)
+
+// unitDurations are the lengths of the rows of every locale's unit table
+var unitDurations = []time.Duration{
+	365 * 24 * time.Hour, 30 * 24 * time.Hour, 7 * 24 * time.Hour,
+	24 * time.Hour, time.Hour, time.Minute, time.Second,
+}
+
+// localeUnits builds a unit table from one row of plural forms per unit,
+// largest unit first, in the order of the categories given
+func localeUnits(categories []string, rows ...[]string) []Unit {
+	units := make([]Unit, len(rows))
+	for i, row := range rows {
+		forms := map[string]string{}
+		for j, category := range categories {
+			forms[category] = row[j]
+		}
+		units[i] = Unit{Singular: row[0], Plural: row[len(row)-1], Duration: unitDurations[i], Forms: forms}
+	}
+	return units
+}
+
+// Orders of the plural forms in the locale tables
+var (
+	oneOther   = []string{"one", "other"}
+	oneFewMany = []string{"one", "few", "many"}
+	otherOnly  = []string{"other"}
+)
+
+// Locales maps language codes to their wording. Durations are inflected
+// for use after "ago"/"in" (e.g. the German dative "vor 2 Tagen").
+var Locales = map[string]Locale{
+	"en": {
+		Units: localeUnits(oneOther,
+			[]string{"year", "years"}, []string{"month", "months"}, []string{"week", "weeks"},
+			[]string{"day", "days"}, []string{"hour", "hours"}, []string{"minute", "minutes"},
+			[]string{"second", "seconds"}),
+		PluralRule: pluralOneOther, UnitFormat: "%s %s", Separator: " ",
+		JustNowText: "just now", PastFormat: "%s ago", FutureFormat: "in %s",
+	},
+	"fr": {
+		Units: localeUnits(oneOther,
+			[]string{"an", "ans"}, []string{"mois", "mois"}, []string{"semaine", "semaines"},
+			[]string{"jour", "jours"}, []string{"heure", "heures"}, []string{"minute", "minutes"},
+			[]string{"seconde", "secondes"}),
+		PluralRule: pluralZeroOne, UnitFormat: "%s %s", Separator: " ",
+		JustNowText: "à l’instant", PastFormat: "il y a %s", FutureFormat: "dans %s",
+		Aliases: map[string]string{"année": "year", "années": "year", "h": "hour", "min": "minute", "sec": "second"},
+	},
+	"de": {
+		Units: localeUnits(oneOther,
+			[]string{"Jahr", "Jahren"}, []string{"Monat", "Monaten"}, []string{"Woche", "Wochen"},
+			[]string{"Tag", "Tagen"}, []string{"Stunde", "Stunden"}, []string{"Minute", "Minuten"},
+			[]string{"Sekunde", "Sekunden"}),
+		PluralRule: pluralOneOther, UnitFormat: "%s %s", Separator: " ",
+		JustNowText: "gerade eben", PastFormat: "vor %s", FutureFormat: "in %s",
+		Aliases: map[string]string{"jahre": "year", "monate": "month", "tage": "day", "std": "hour", "min": "minute", "sek": "second"},
+	},
+	"es": {
+		Units: localeUnits(oneOther,
+			[]string{"año", "años"}, []string{"mes", "meses"}, []string{"semana", "semanas"},
+			[]string{"día", "días"}, []string{"hora", "horas"}, []string{"minuto", "minutos"},
+			[]string{"segundo", "segundos"}),
+		PluralRule: pluralOneOther, UnitFormat: "%s %s", Separator: " ",
+		JustNowText: "justo ahora", PastFormat: "hace %s", FutureFormat: "dentro de %s",
+		Aliases: map[string]string{"h": "hour", "min": "minute", "seg": "second"},
+	},
+	"it": {
+		Units: localeUnits(oneOther,
+			[]string{"anno", "anni"}, []string{"mese", "mesi"}, []string{"settimana", "settimane"},
+			[]string{"giorno", "giorni"}, []string{"ora", "ore"}, []string{"minuto", "minuti"},
+			[]string{"secondo", "secondi"}),
+		PluralRule: pluralOneOther, UnitFormat: "%s %s", Separator: " ",
+		JustNowText: "proprio ora", PastFormat: "%s fa", FutureFormat: "tra %s",
+		Aliases: map[string]string{"h": "hour", "min": "minute", "sec": "second"},
+	},
+	"pt": {
+		Units: localeUnits(oneOther,
+			[]string{"ano", "anos"}, []string{"mês", "meses"}, []string{"semana", "semanas"},
+			[]string{"dia", "dias"}, []string{"hora", "horas"}, []string{"minuto", "minutos"},
+			[]string{"segundo", "segundos"}),
+		PluralRule: pluralZeroOne, UnitFormat: "%s %s", Separator: " ",
+		JustNowText: "agora mesmo", PastFormat: "há %s", FutureFormat: "em %s",
+		Aliases: map[string]string{"mes": "month", "meses": "month", "h": "hour", "min": "minute", "seg": "second"},
+	},
+	"nl": {
+		Units: localeUnits(oneOther,
+			[]string{"jaar", "jaar"}, []string{"maand", "maanden"}, []string{"week", "weken"},
+			[]string{"dag", "dagen"}, []string{"uur", "uur"}, []string{"minuut", "minuten"},
+			[]string{"seconde", "seconden"}),
+		PluralRule: pluralOneOther, UnitFormat: "%s %s", Separator: " ",
+		JustNowText: "zojuist", PastFormat: "%s geleden", FutureFormat: "over %s",
+		Aliases: map[string]string{"uren": "hour", "u": "hour", "min": "minute", "sec": "second"},
+	},
+	"ru": {
+		Units: localeUnits(oneFewMany,
+			[]string{"год", "года", "лет"}, []string{"месяц", "месяца", "месяцев"},
+			[]string{"неделю", "недели", "недель"}, []string{"день", "дня", "дней"},
+			[]string{"час", "часа", "часов"}, []string{"минуту", "минуты", "минут"},
+			[]string{"секунду", "секунды", "секунд"}),
+		PluralRule: pluralSlavic, UnitFormat: "%s %s", Separator: " ",
+		JustNowText: "только что", PastFormat: "%s назад", FutureFormat: "через %s",
+		Aliases: map[string]string{"неделя": "week", "минута": "minute", "секунда": "second", "ч": "hour", "мин": "minute", "сек": "second"},
+	},
+	"pl": {
+		Units: localeUnits(oneFewMany,
+			[]string{"rok", "lata", "lat"}, []string{"miesiąc", "miesiące", "miesięcy"},
+			[]string{"tydzień", "tygodnie", "tygodni"}, []string{"dzień", "dni", "dni"},
+			[]string{"godzinę", "godziny", "godzin"}, []string{"minutę", "minuty", "minut"},
+			[]string{"sekundę", "sekundy", "sekund"}),
+		PluralRule: pluralPolish, UnitFormat: "%s %s", Separator: " ",
+		JustNowText: "przed chwilą", PastFormat: "%s temu", FutureFormat: "za %s",
+		Aliases: map[string]string{"godzina": "hour", "minuta": "minute", "sekunda": "second", "godz": "hour", "min": "minute", "sek": "second"},
+	},
+	"ja": {
+		Units: localeUnits(otherOnly,
+			[]string{"年"}, []string{"か月"}, []string{"週間"}, []string{"日"},
+			[]string{"時間"}, []string{"分"}, []string{"秒"}),
+		PluralRule: pluralOther, UnitFormat: "%s%s",
+		JustNowText: "たった今", PastFormat: "%s前", FutureFormat: "%s後",
+		Aliases: map[string]string{"ヶ月": "month", "カ月": "month", "週": "week"},
+	},
+	"zh": {
+		Units: localeUnits(otherOnly,
+			[]string{"年"}, []string{"个月"}, []string{"周"}, []string{"天"},
+			[]string{"小时"}, []string{"分钟"}, []string{"秒"}),
+		PluralRule: pluralOther, UnitFormat: "%s%s",
+		JustNowText: "刚刚", PastFormat: "%s前", FutureFormat: "%s后",
+		Aliases: map[string]string{"月": "month", "星期": "week", "日": "day", "分": "minute"},
+	},
+}
+
+// LocaleNames lists the supported language codes, sorted
+func LocaleNames() []string {
+	names := make([]string, 0, len(Locales))
+	for name := range Locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewLocaleFormatter returns a Formatter worded in the given language
+func NewLocaleFormatter(lang string) (*Formatter, error) {
+	l, ok := Locales[lang]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language %q", lang)
+	}
+	f := NewFormatter()
+	f.Units = l.Units
+	f.PluralRule = l.PluralRule
+	f.UnitFormat = l.UnitFormat
+	f.JustNowText = l.JustNowText
+	f.PastFormat = l.PastFormat
+	f.FutureFormat = l.FutureFormat
+	f.Separator = l.Separator
+	return f, nil
+}
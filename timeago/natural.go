@@ -0,0 +1,138 @@
+package timeago
+
+import (
+	"strings"
+	"time"
+)
+
+// weekdayNames maps full and abbreviated English day names
+var weekdayNames = map[string]time.Weekday{
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+	"sunday": time.Sunday, "sun": time.Sunday,
+}
+
+// ParseTime parses a natural-language instant with DefaultParser
+func ParseTime(input string, now time.Time) (time.Time, error) {
+	return DefaultParser.ParseTime(input, now)
+}
+
+// atClock returns the given wall-clock time on d's calendar day
+func atClock(d time.Time, h, m, s int) time.Time {
+	return time.Date(d.Year(), d.Month(), d.Day(), h, m, s, 0, d.Location())
+}
+
+// parseWeekday resolves "friday", "this friday", "next friday" and
+// "last friday" to midnight of that day. Bare and "this" include today,
+// "next" and "last" never do.
+func parseWeekday(s string, now time.Time) (time.Time, bool) {
+	modifier, name, found := strings.Cut(s, " ")
+	if !found {
+		modifier, name = "", s
+	}
+	weekday, ok := weekdayNames[name]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	today := startOfDay(now)
+	days := (int(weekday) - int(today.Weekday()) + 7) % 7
+	switch modifier {
+	case "", "this":
+	case "next":
+		if days == 0 {
+			days = 7
+		}
+	case "last":
+		days -= 7
+	default:
+		return time.Time{}, false
+	}
+	return today.AddDate(0, 0, days), true
+}
+
+// parseDay parses the day part of an instant: a weekday or any phrase
+// naming a single period, which resolves to its start
+func (p *Parser) parseDay(s string, now time.Time) (time.Time, bool) {
+	if t, ok := parseWeekday(s, now); ok {
+		return t, true
+	}
+	if r, ok := p.parsePeriod(s, now); ok {
+		return r.Start, true
+	}
+	return time.Time{}, false
+}
+
+// ParseTime parses a natural-language instant relative to now (whose
+// location sets the calendar). Supported forms:
+//
+//	now, today, yesterday, tomorrow, 2024-03-01 (midnight when no time is given)
+//	friday, next tuesday, last friday (next and last exclude today)
+//	any of the above followed by [at] 3pm, 15:30, noon or midnight, or
+//	preceded by it ("9am tomorrow")
+//	3pm, at noon (today)
+//	2 hours ago, in 3 days
+//
+// Other phrases accepted by ParseRange for a single period ("last week",
+// "Q1 2024") resolve to the start of the period.
+func (p *Parser) ParseTime(input string, now time.Time) (time.Time, error) {
+	if err := p.checkInput(input); err != nil {
+		return time.Time{}, err
+	}
+	s := strings.TrimPrefix(normalizePhrase(input), "at ")
+
+	switch {
+	case s == "":
+		return time.Time{}, &ErrInvalidFormat{Input: input}
+	case s == "now":
+		return now, nil
+	case strings.HasSuffix(s, " ago"):
+		d, err := p.ParseDuration(strings.TrimSuffix(s, " ago"))
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(-d), nil
+	case strings.HasPrefix(s, "in "):
+		d, err := p.ParseDuration(strings.TrimPrefix(s, "in "))
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(d), nil
+	}
+
+	if h, m, sec, ok := parseClockOfDay(s); ok {
+		return atClock(now, h, m, sec), nil
+	}
+	if t, ok := p.parseDay(s, now); ok {
+		return t, nil
+	}
+
+	// "<day> [at] <time>", e.g. "tomorrow 3pm" or "last friday at noon"
+	fields := strings.Fields(s)
+	for split := len(fields) - 1; split > 0; split-- {
+		h, m, sec, ok := parseClockOfDay(strings.Join(fields[split:], " "))
+		if !ok {
+			continue
+		}
+		day, ok := p.parseDay(strings.TrimSuffix(strings.Join(fields[:split], " "), " at"), now)
+		if !ok {
+			break
+		}
+		return atClock(day, h, m, sec), nil
+	}
+	// "<time> <day>", e.g. "9am tomorrow"
+	for split := 1; split < len(fields); split++ {
+		h, m, sec, ok := parseClockOfDay(strings.TrimPrefix(strings.Join(fields[:split], " "), "at "))
+		if !ok {
+			continue
+		}
+		if day, ok := p.parseDay(strings.Join(fields[split:], " "), now); ok {
+			return atClock(day, h, m, sec), nil
+		}
+	}
+	return time.Time{}, &ErrInvalidFormat{Input: input}
+}
@@ -0,0 +1,76 @@
+package timeago
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no zoneinfo: %v", err)
+	}
+	at := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, ny)
+	}
+	// A Wednesday, a few days after the March change to daylight time
+	wednesday := at(2024, time.March, 13, 10, 30)
+
+	tests := []struct {
+		name  string
+		now   time.Time
+		input string
+		want  time.Time
+	}{
+		{"now", wednesday, "now", at(2024, time.March, 13, 10, 30)},
+		{"yesterday", wednesday, "yesterday", at(2024, time.March, 12, 0, 0)},
+		{"tomorrow at a time", wednesday, "tomorrow 3pm", at(2024, time.March, 14, 15, 0)},
+		{"time before the day", wednesday, "9am tomorrow", at(2024, time.March, 14, 9, 0)},
+		{"next weekday", wednesday, "next tuesday", at(2024, time.March, 19, 0, 0)},
+		{"last weekday at noon", wednesday, "last friday at noon", at(2024, time.March, 8, 12, 0)},
+		{"bare weekday is ahead", wednesday, "friday", at(2024, time.March, 15, 0, 0)},
+		{"this weekday includes today", wednesday, "this wednesday", at(2024, time.March, 13, 0, 0)},
+		{"next weekday skips today", wednesday, "next wednesday", at(2024, time.March, 20, 0, 0)},
+		{"last weekday skips today", wednesday, "last wednesday", at(2024, time.March, 6, 0, 0)},
+		{"time today", wednesday, "at 17:45", at(2024, time.March, 13, 17, 45)},
+		{"midnight", wednesday, "midnight", at(2024, time.March, 13, 0, 0)},
+		{"ago", wednesday, "2 hours ago", at(2024, time.March, 13, 8, 30)},
+		{"in", wednesday, "in 3 days", at(2024, time.March, 16, 10, 30)},
+
+		// Calendar days keep their wall-clock time across daylight saving
+		// changes, fixed durations do not
+		{"yesterday across spring forward", at(2024, time.March, 10, 12, 0), "yesterday 9am", at(2024, time.March, 9, 9, 0)},
+		{"tomorrow across fall back", at(2024, time.November, 2, 18, 0), "tomorrow 9am", at(2024, time.November, 3, 9, 0)},
+		{"fixed day across fall back", at(2024, time.November, 2, 18, 0), "in 1 day", at(2024, time.November, 3, 17, 0)},
+		{"last weekday across spring forward", at(2024, time.March, 11, 8, 0), "last saturday at noon", at(2024, time.March, 9, 12, 0)},
+
+		{"month rollover", at(2024, time.January, 31, 20, 0), "tomorrow", at(2024, time.February, 1, 0, 0)},
+		{"leap day", at(2024, time.February, 28, 20, 0), "tomorrow 8am", at(2024, time.February, 29, 8, 0)},
+		{"back to the previous month", at(2024, time.March, 1, 1, 0), "yesterday", at(2024, time.February, 29, 0, 0)},
+		{"year rollover", at(2024, time.December, 31, 22, 0), "tomorrow 9am", at(2025, time.January, 1, 9, 0)},
+		{"weekday in the next year", at(2024, time.December, 31, 22, 0), "next monday", at(2025, time.January, 6, 0, 0)},
+		{"back to the previous year", at(2025, time.January, 1, 6, 0), "last friday", at(2024, time.December, 27, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTime(tt.input, tt.now)
+			if err != nil {
+				t.Fatalf("ParseTime(%q): %v", tt.input, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseTime(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTimeInvalid(t *testing.T) {
+	now := time.Date(2024, time.March, 13, 10, 30, 0, 0, time.UTC)
+	for _, input := range []string{"", "someday", "next funday", "25:00", "tomorrow at 3xm"} {
+		if _, err := ParseTime(input, now); err == nil {
+			t.Errorf("ParseTime(%q) succeeded, want an error", input)
+		} else if _, ok := err.(*ErrInvalidFormat); !ok {
+			t.Errorf("ParseTime(%q) = %T, want *ErrInvalidFormat", input, err)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package timeago
+
+import (
+	"strings"
+	"unicode"
+)
+
+// fillerWords are hedges that carry no meaning for the parser, as found in
+// text copied from chat messages ("about 2 hours ago")
+var fillerWords = map[string]bool{
+	"about": true, "roughly": true, "around": true, "approximately": true,
+	"approx": true, "circa": true, "ca": true,
+}
+
+// normalizePhrase lowercases input, turns punctuation other than the date
+// and clock separators (- / : and inner dots) into spaces, drops filler
+// words and a trailing "or so", and collapses whitespace
+func normalizePhrase(input string) string {
+	s := strings.Map(func(r rune) rune {
+		switch {
+		case r == '-' || r == '/' || r == ':' || r == '.' || r == '+':
+			return r
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			return ' '
+		}
+		return unicode.ToLower(r)
+	}, input)
+
+	words := strings.Fields(s)
+	kept := words[:0]
+	for _, w := range words {
+		w = strings.TrimRight(w, ".")
+		if w != "" && !fillerWords[w] {
+			kept = append(kept, w)
+		}
+	}
+	if n := len(kept); n >= 2 && kept[n-2] == "or" && kept[n-1] == "so" {
+		kept = kept[:n-2]
+	}
+	return strings.Join(kept, " ")
+}
@@ -0,0 +1,312 @@
+package timeago
+
+import (
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// durationUnits maps every accepted unit spelling to its length
+var durationUnits = map[string]time.Duration{
+	"year":         365 * 24 * time.Hour,
+	"years":        365 * 24 * time.Hour,
+	"y":            365 * 24 * time.Hour,
+	"month":        30 * 24 * time.Hour,
+	"months":       30 * 24 * time.Hour,
+	"week":         7 * 24 * time.Hour,
+	"weeks":        7 * 24 * time.Hour,
+	"w":            7 * 24 * time.Hour,
+	"day":          24 * time.Hour,
+	"days":         24 * time.Hour,
+	"d":            24 * time.Hour,
+	"hour":         time.Hour,
+	"hours":        time.Hour,
+	"h":            time.Hour,
+	"minute":       time.Minute,
+	"minutes":      time.Minute,
+	"min":          time.Minute,
+	"m":            time.Minute,
+	"second":       time.Second,
+	"seconds":      time.Second,
+	"sec":          time.Second,
+	"s":            time.Second,
+	"millisecond":  time.Millisecond,
+	"milliseconds": time.Millisecond,
+	"ms":           time.Millisecond,
+}
+
+// UnitWords lists the unit words and abbreviations durations accept, sorted
+func UnitWords() []string {
+	words := make([]string, 0, len(durationUnits))
+	for word := range durationUnits {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+	return words
+}
+
+// UnitDuration returns the length of a unit word or abbreviation ("hours",
+// "min"), or *ErrUnknownUnit
+func UnitDuration(word string) (time.Duration, error) {
+	unit, ok := durationUnits[strings.ToLower(word)]
+	if !ok {
+		return 0, &ErrUnknownUnit{Unit: word}
+	}
+	return unit, nil
+}
+
+// goDuration reads Go duration syntax ("2h30m45s", "1500ms", "1.5h") with
+// time.ParseDuration. Where both grammars accept an input they agree, but
+// only Go's has the us and ns units and signs.
+func goDuration(input string) (time.Duration, bool) {
+	if strings.Contains(input, " ") {
+		return 0, false
+	}
+	d, err := time.ParseDuration(input)
+	return d, err == nil
+}
+
+// durationTerm matches a number, possibly decimal or signed, followed by a
+// unit
+var durationTerm = regexp.MustCompile(`(^|\s)?([+-]?)(\d+(?:\.\d+)?)\s*([a-zA-Z]+)`)
+
+// durationTerms returns the signed count and the unit of up to n terms of
+// input. A sign only counts at the start of a word ("1 day -2 hours"), not
+// inside a range such as "5-10 minutes", and covers the whole word, so
+// "-37d4h" is minus 37 days and 4 hours.
+func durationTerms(input string, n int) [][2]string {
+	var terms [][2]string
+	sign := ""
+	for _, m := range durationTerm.FindAllStringSubmatchIndex(input, n) {
+		if m[2] >= 0 {
+			sign = strings.TrimPrefix(input[m[4]:m[5]], "+")
+		}
+		terms = append(terms, [2]string{sign + input[m[6]:m[7]], input[m[8]:m[9]]})
+	}
+	return terms
+}
+
+// termDuration multiplies a decimal count ("2", "1.5") by a unit exactly,
+// truncating toward zero; ok is false beyond max in either direction
+func termDuration(count string, unit, max time.Duration) (time.Duration, bool) {
+	r, ok := new(big.Rat).SetString(count)
+	if !ok {
+		return 0, false
+	}
+	r.Mul(r, new(big.Rat).SetInt64(int64(unit)))
+	if new(big.Rat).Abs(r).Cmp(new(big.Rat).SetInt64(int64(max))) > 0 {
+		return 0, false
+	}
+	return time.Duration(new(big.Int).Quo(r.Num(), r.Denom()).Int64()), true
+}
+
+// Limits bounds the work a Parser accepts to do, so untrusted input (HTTP
+// queries, log streams) cannot trigger pathological behavior
+type Limits struct {
+	// MaxInputBytes is the longest input accepted
+	MaxInputBytes int
+	// MaxTokens is the maximum number of number/unit terms in a duration
+	MaxTokens int
+	// MaxMagnitude is the largest duration accepted, in either direction
+	MaxMagnitude time.Duration
+}
+
+// DefaultLimits are generous for humans and cheap for machines
+var DefaultLimits = Limits{
+	MaxInputBytes: 256,
+	MaxTokens:     16,
+	MaxMagnitude:  200 * 365 * 24 * time.Hour,
+}
+
+// Parser is the hardened entry point for every parsing API. The zero value
+// is not usable, use NewParser or DefaultParser.
+type Parser struct {
+	Limits Limits
+}
+
+// NewParser returns a Parser enforcing the given limits
+func NewParser(limits Limits) *Parser {
+	return &Parser{Limits: limits}
+}
+
+// DefaultParser backs the package-level parsing functions
+var DefaultParser = NewParser(DefaultLimits)
+
+// ParseDuration parses a duration with DefaultParser
+func ParseDuration(input string) (time.Duration, error) {
+	return DefaultParser.ParseDuration(input)
+}
+
+// ParseDate parses a calendar date with DefaultParser
+func ParseDate(input string, loc *time.Location) (time.Time, error) {
+	return DefaultParser.ParseDate(input, loc)
+}
+
+// checkInput rejects inputs longer than MaxInputBytes
+func (p *Parser) checkInput(input string) error {
+	if len(input) > p.Limits.MaxInputBytes {
+		return &ErrLimitExceeded{Limit: "input length", Max: int64(p.Limits.MaxInputBytes)}
+	}
+	return nil
+}
+
+// magnitudeError reports a duration beyond MaxMagnitude
+func (p *Parser) magnitudeError() error {
+	return &ErrLimitExceeded{Limit: "magnitude", Max: int64(p.Limits.MaxMagnitude)}
+}
+
+// ParseDuration parses a human-readable duration such as "2 hours",
+// "1 day 5 hours", "2h 30m" or "1 day -2 hours", a Go duration ("1.5h",
+// "1500ms") or an ISO 8601 duration ("P1DT2H30M"). A trailing "ago",
+// punctuation and filler words ("about", "roughly") are ignored and a
+// plain number is read as milliseconds. Errors are *ErrInvalidFormat,
+// *ErrUnknownUnit or *ErrLimitExceeded.
+func (p *Parser) ParseDuration(input string) (time.Duration, error) {
+	if err := p.checkInput(input); err != nil {
+		return 0, err
+	}
+
+	input = strings.TrimSpace(strings.TrimSuffix(normalizePhrase(input), "ago"))
+	input, _ = expandISODuration(input)
+
+	// Try to parse as a plain number (milliseconds)
+	if val, err := strconv.ParseInt(input, 10, 64); err == nil {
+		maxMs := int64(p.Limits.MaxMagnitude / time.Millisecond)
+		if val > maxMs || val < -maxMs {
+			return 0, p.magnitudeError()
+		}
+		return time.Duration(val) * time.Millisecond, nil
+	}
+
+	if d, ok := goDuration(input); ok {
+		if d > p.Limits.MaxMagnitude || d < -p.Limits.MaxMagnitude {
+			return 0, p.magnitudeError()
+		}
+		return d, nil
+	}
+
+	matches := durationTerms(input, p.Limits.MaxTokens+1)
+	if len(matches) == 0 {
+		return 0, &ErrInvalidFormat{Input: input}
+	}
+	if len(matches) > p.Limits.MaxTokens {
+		return 0, &ErrLimitExceeded{Limit: "token count", Max: int64(p.Limits.MaxTokens)}
+	}
+
+	var total time.Duration
+	for _, match := range matches {
+		unit := strings.ToLower(match[1])
+		multiplier, ok := durationUnits[unit]
+		if !ok {
+			return 0, &ErrUnknownUnit{Unit: unit}
+		}
+
+		d, ok := termDuration(match[0], multiplier, p.Limits.MaxMagnitude)
+		if !ok {
+			return 0, p.magnitudeError()
+		}
+		total += d
+		if total > p.Limits.MaxMagnitude || total < -p.Limits.MaxMagnitude {
+			return 0, p.magnitudeError()
+		}
+	}
+
+	return total, nil
+}
+
+// DurationTerm is one term of a parsed duration, e.g. "2 weeks"
+type DurationTerm struct {
+	Text     string        // count and unit as read, e.g. "-1.5 hours"
+	Duration time.Duration // fixed length: years are 365 days, months 30
+	Calendar bool          // a year, month, week or day, which add/sub follow on the calendar
+}
+
+// ParseDurationTerms splits a duration with DefaultParser
+func ParseDurationTerms(input string) ([]DurationTerm, error) {
+	return DefaultParser.ParseDurationTerms(input)
+}
+
+// ParseDurationTerms splits a duration accepted by ParseDuration into its
+// terms, to show how a total was reached. ISO 8601 durations yield their
+// expanded terms; a Go duration or plain milliseconds is a single term.
+func (p *Parser) ParseDurationTerms(input string) ([]DurationTerm, error) {
+	total, err := p.ParseDuration(input)
+	if err != nil {
+		return nil, err
+	}
+
+	input = strings.TrimSpace(strings.TrimSuffix(normalizePhrase(input), "ago"))
+	input, _ = expandISODuration(input)
+	if _, err := strconv.ParseInt(input, 10, 64); err == nil {
+		return []DurationTerm{{Text: input + " ms", Duration: total}}, nil
+	}
+	if _, ok := goDuration(input); ok {
+		return []DurationTerm{{Text: input, Duration: total}}, nil
+	}
+
+	var terms []DurationTerm
+	for _, match := range durationTerms(input, p.Limits.MaxTokens) {
+		unit := durationUnits[strings.ToLower(match[1])]
+		d, _ := termDuration(match[0], unit, p.Limits.MaxMagnitude)
+		terms = append(terms, DurationTerm{
+			Text:     match[0] + " " + match[1],
+			Duration: d,
+			Calendar: unit >= 24*time.Hour,
+		})
+	}
+	return terms, nil
+}
+
+// slashDate matches numeric dates such as 03/04/2024 or 3-4-2024
+var slashDate = regexp.MustCompile(`^(\d{1,2})[/.-](\d{1,2})[/.-](\d{4})$`)
+
+// ParseDate parses a calendar date in loc. ISO dates (2024-03-04) are
+// unambiguous; day/month forms (04/03/2024) are accepted when only one
+// reading is a valid date and fail with *ErrAmbiguousDate otherwise.
+func (p *Parser) ParseDate(input string, loc *time.Location) (time.Time, error) {
+	if err := p.checkInput(input); err != nil {
+		return time.Time{}, err
+	}
+
+	input = strings.TrimSpace(input)
+	if t, err := time.ParseInLocation("2006-01-02", input, loc); err == nil {
+		return t, nil
+	}
+
+	m := slashDate.FindStringSubmatch(input)
+	if m == nil {
+		return time.Time{}, &ErrInvalidFormat{Input: input}
+	}
+	a, _ := strconv.Atoi(m[1])
+	b, _ := strconv.Atoi(m[2])
+	year, _ := strconv.Atoi(m[3])
+
+	// Collect the month/day and day/month readings that are real dates
+	var candidates []time.Time
+	for _, md := range [][2]int{{a, b}, {b, a}} {
+		month, day := md[0], md[1]
+		if month < 1 || month > 12 || day < 1 {
+			continue
+		}
+		t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, loc)
+		if t.Day() != day {
+			continue
+		}
+		if len(candidates) == 1 && candidates[0].Equal(t) {
+			continue
+		}
+		candidates = append(candidates, t)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return time.Time{}, &ErrInvalidFormat{Input: input}
+	case 1:
+		return candidates[0], nil
+	default:
+		return time.Time{}, &ErrAmbiguousDate{Input: input, Candidates: candidates}
+	}
+}
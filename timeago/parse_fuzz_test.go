@@ -0,0 +1,229 @@
+package timeago
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fuzzLimits are tight enough for the fuzzer to reach every bound
+var fuzzLimits = Limits{
+	MaxInputBytes: 64,
+	MaxTokens:     4,
+	MaxMagnitude:  1000 * time.Hour,
+}
+
+// fuzzNow is the fixed reference instant of the relative parsers
+var fuzzNow = time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC)
+
+// durationSeeds are representative inputs shared by the duration targets
+var durationSeeds = []string{
+	"", "0", "1500", "-1500", "2 hours", "1 day 5 hours", "2h 30m", "1.5h",
+	"1500ms", "P1DT2H30M", "PT0.5S", "about 3 days ago", "1 day -2 hours",
+	"-37d4h", "5-10 minutes", "1 fortnight", "1e9 seconds", "999999999999 years",
+	"1s 1s 1s 1s 1s", "1.000000000000000000001 ms", "2 hours.",
+}
+
+// checkTyped fails unless err is one of the package's error types
+func checkTyped(t *testing.T, input string, err error) {
+	t.Helper()
+	var (
+		invalid   *ErrInvalidFormat
+		unit      *ErrUnknownUnit
+		ambiguous *ErrAmbiguousDate
+		limit     *ErrLimitExceeded
+	)
+	if !errors.As(err, &invalid) && !errors.As(err, &unit) && !errors.As(err, &ambiguous) && !errors.As(err, &limit) {
+		t.Fatalf("%q: untyped error %T: %v", input, err, err)
+	}
+}
+
+// checkLength fails unless an input over MaxInputBytes was rejected with
+// *ErrLimitExceeded
+func checkLength(t *testing.T, input string, err error) {
+	t.Helper()
+	var limit *ErrLimitExceeded
+	if len(input) > fuzzLimits.MaxInputBytes && !errors.As(err, &limit) {
+		t.Fatalf("%q: %d bytes accepted past MaxInputBytes, err %v", input, len(input), err)
+	}
+}
+
+func FuzzParseDuration(f *testing.F) {
+	p := NewParser(fuzzLimits)
+	for _, tc := range []struct {
+		input string
+		limit string
+	}{
+		{strings.Repeat("1s ", fuzzLimits.MaxTokens+1), "token count"},
+		{"1001 hours", "magnitude"},
+		{"-42 days", "magnitude"},
+		{"500h 501h", "magnitude"},
+		{"3600000001", "magnitude"},
+		{strings.Repeat("1", fuzzLimits.MaxInputBytes+1), "input length"},
+	} {
+		_, err := p.ParseDuration(tc.input)
+		var limit *ErrLimitExceeded
+		if !errors.As(err, &limit) || limit.Limit != tc.limit {
+			f.Fatalf("%q: got %v, want the %s limit", tc.input, err, tc.limit)
+		}
+	}
+
+	for _, seed := range durationSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		d, err := p.ParseDuration(input)
+		checkLength(t, input, err)
+		if err != nil {
+			checkTyped(t, input, err)
+			return
+		}
+		if d > fuzzLimits.MaxMagnitude || d < -fuzzLimits.MaxMagnitude {
+			t.Fatalf("%q: %v is past MaxMagnitude", input, d)
+		}
+
+		// The terms account for the whole total, within MaxTokens
+		terms, err := p.ParseDurationTerms(input)
+		if err != nil {
+			t.Fatalf("%q: ParseDuration accepted it but ParseDurationTerms failed: %v", input, err)
+		}
+		if len(terms) > fuzzLimits.MaxTokens {
+			t.Fatalf("%q: %d terms accepted past MaxTokens", input, len(terms))
+		}
+		var sum time.Duration
+		for _, term := range terms {
+			sum += term.Duration
+		}
+		if sum != d {
+			t.Fatalf("%q: terms sum to %v, total is %v", input, sum, d)
+		}
+	})
+}
+
+func FuzzParseCalendarDuration(f *testing.F) {
+	p := NewParser(fuzzLimits)
+	for _, seed := range durationSeeds {
+		f.Add(seed)
+	}
+	f.Add("1 year 2 months 3 weeks 4 days")
+	f.Add("-1.5 days")
+	f.Fuzz(func(t *testing.T, input string) {
+		c, err := p.ParseCalendarDuration(input)
+		_, fixedErr := p.ParseDuration(input)
+		checkLength(t, input, err)
+		if (err == nil) != (fixedErr == nil) {
+			t.Fatalf("%q: calendar error %v, fixed error %v", input, err, fixedErr)
+		}
+		if err != nil {
+			checkTyped(t, input, err)
+			return
+		}
+		// Within the magnitude, every field fits a plain day count
+		days := int64(c.Years)*365 + int64(c.Months)*30 + int64(c.Days)
+		if bound := int64(fuzzLimits.MaxMagnitude / (24 * time.Hour)); days > bound || days < -bound {
+			t.Fatalf("%q: %+v is past MaxMagnitude", input, c)
+		}
+	})
+}
+
+func FuzzParseTime(f *testing.F) {
+	p := NewParser(fuzzLimits)
+	for _, seed := range []string{
+		"", "now", "today", "yesterday", "tomorrow 3pm", "9am tomorrow",
+		"next tuesday", "last friday at noon", "at midnight", "15:30", "3pm",
+		"2024-02-29", "2 hours ago", "in 3 days", "in 1001 hours", "last week",
+		"q1 2024", "march 2024", "25:00", "12:60pm", "friday friday", "in  ago",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		_, err := p.ParseTime(input, fuzzNow)
+		checkLength(t, input, err)
+		if err != nil {
+			checkTyped(t, input, err)
+		}
+	})
+}
+
+func FuzzParseRange(f *testing.F) {
+	p := NewParser(fuzzLimits)
+	for _, seed := range []string{
+		"", "today", "this week", "last month", "next quarter", "past 3 days",
+		"last 99999999999999999999 years", "q4", "q2 2024", "2024", "2024-02",
+		"march 2024", "yesterday 9am to 5pm", "9am to 5pm", "10pm to 2am",
+		"2024-03-01 to 2024-03-05", "tomorrow to yesterday", " to ", "to to to",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		r, err := p.ParseRange(input, fuzzNow)
+		checkLength(t, input, err)
+		if err != nil {
+			checkTyped(t, input, err)
+			return
+		}
+		if !r.End.After(r.Start) {
+			t.Fatalf("%q: empty range %v to %v", input, r.Start, r.End)
+		}
+	})
+}
+
+func FuzzParseDate(f *testing.F) {
+	p := NewParser(fuzzLimits)
+	for _, seed := range []string{
+		"", "2024-03-04", "03/04/2024", "13/04/2024", "04/13/2024", "4.4.2024",
+		"31/02/2024", "29-02-2023", "00/00/0000", "2024-13-01", "1/1/99999",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		d, err := p.ParseDate(input, time.UTC)
+		checkLength(t, input, err)
+		if err != nil {
+			checkTyped(t, input, err)
+			var ambiguous *ErrAmbiguousDate
+			if errors.As(err, &ambiguous) && len(ambiguous.Candidates) < 2 {
+				t.Fatalf("%q: ambiguous with %d candidates", input, len(ambiguous.Candidates))
+			}
+			return
+		}
+		if !d.Equal(startOfDay(d)) {
+			t.Fatalf("%q: %v is not a midnight", input, d)
+		}
+	})
+}
+
+func FuzzTranslate(f *testing.F) {
+	p := NewParser(fuzzLimits)
+	for _, seed := range []struct{ input, lang string }{
+		{"hace 2 horas", "es"}, {"3 Tage", "de"}, {"dans 2 jours", "fr"},
+		{"2 Stunden und 5 Minuten", ""}, {"2時間", ""}, {"2 hours", "en"},
+		{"hace", "es"}, {"il y a 1 jour", "xx"}, {"", ""},
+		{"99999999999999999999 días", "es"},
+	} {
+		f.Add(seed.input, seed.lang)
+	}
+	f.Fuzz(func(t *testing.T, input, lang string) {
+		english, err := p.Translate(input, lang)
+		if _, known := Locales[lang]; !known && lang != "" {
+			// Unknown languages are a usage error, not a parse error
+			if err == nil {
+				t.Fatalf("%q: unknown language %q accepted", input, lang)
+			}
+			return
+		}
+		checkLength(t, input, err)
+		if err != nil {
+			checkTyped(t, input, err)
+			return
+		}
+		if english == "" {
+			t.Fatalf("%q in %q: empty translation", input, lang)
+		}
+		// The English phrase feeds the other parsers, which must cope
+		if _, err := p.ParseTime(english, fuzzNow); err != nil {
+			checkTyped(t, english, err)
+		}
+	})
+}
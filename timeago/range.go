@@ -0,0 +1,327 @@
+package timeago
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Range is the half-open interval [Start, End)
+type Range struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ParseRange parses a range phrase with DefaultParser
+func ParseRange(input string, now time.Time) (Range, error) {
+	return DefaultParser.ParseRange(input, now)
+}
+
+var (
+	relativePeriod = regexp.MustCompile(`^(this|last|next|previous|current) (day|week|month|quarter|year)$`)
+	countedPeriod  = regexp.MustCompile(`^(last|past|next) (\d+) (minute|hour|day|week|month|year)s?$`)
+	quarterPeriod  = regexp.MustCompile(`^q([1-4])(?: (\d{4}))?$`)
+	yearPeriod     = regexp.MustCompile(`^\d{4}$`)
+	monthPeriod    = regexp.MustCompile(`^\d{4}-\d{2}$`)
+	clockOfDay     = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(?::(\d{2}))? ?(am|pm)?$`)
+)
+
+// monthNames maps full and abbreviated English month names
+var monthNames = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+// startOfDay returns midnight of t's calendar day in t's location
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// startOfWeek returns midnight of the Monday starting t's ISO week
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	d := startOfDay(t)
+	return time.Date(d.Year(), d.Month(), d.Day()-offset, 0, 0, 0, 0, d.Location())
+}
+
+// period returns the calendar period of the given unit containing t,
+// shifted by offset periods
+func period(unit string, t time.Time, offset int) Range {
+	loc := t.Location()
+	var start time.Time
+	var years, months, days int
+	switch unit {
+	case "day":
+		start = startOfDay(t)
+		days = 1
+	case "week":
+		start = startOfWeek(t)
+		days = 7
+	case "month":
+		start = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+		months = 1
+	case "quarter":
+		start = time.Date(t.Year(), (t.Month()-1)/3*3+1, 1, 0, 0, 0, 0, loc)
+		months = 3
+	default:
+		start = time.Date(t.Year(), 1, 1, 0, 0, 0, 0, loc)
+		years = 1
+	}
+	start = start.AddDate(years*offset, months*offset, days*offset)
+	return Range{Start: start, End: start.AddDate(years, months, days)}
+}
+
+// Period returns the calendar day, week (from Monday), month, quarter or
+// year containing t in t's location, shifted by offset periods. Bounds are
+// local midnights, so days are 23 or 25 hours long across DST changes.
+func Period(unit string, t time.Time, offset int) (Range, error) {
+	switch unit {
+	case "day", "week", "month", "quarter", "year":
+		return period(unit, t, offset), nil
+	}
+	return Range{}, &ErrUnknownUnit{Unit: unit}
+}
+
+// parseClockOfDay parses "9am", "5:30pm", "17:00", "noon" or "midnight"
+// into hour, minute and second
+func parseClockOfDay(s string) (int, int, int, bool) {
+	switch s {
+	case "noon":
+		return 12, 0, 0, true
+	case "midnight":
+		return 0, 0, 0, true
+	}
+	m := clockOfDay.FindStringSubmatch(s)
+	// A bare number is a year or a count, not a time
+	if m == nil || (m[2] == "" && m[4] == "") {
+		return 0, 0, 0, false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi(m[2])
+	second, _ := strconv.Atoi(m[3])
+	switch m[4] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, 0, false
+		}
+		hour %= 12
+		if m[4] == "pm" {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 || second > 59 {
+		return 0, 0, 0, false
+	}
+	return hour, minute, second, true
+}
+
+// parsePeriod parses a phrase naming a whole period ("last week", "Q1 2024",
+// "march 2024", "yesterday", "2024-03-01")
+func (p *Parser) parsePeriod(s string, now time.Time) (Range, bool) {
+	loc := now.Location()
+
+	switch s {
+	case "today":
+		return period("day", now, 0), true
+	case "yesterday":
+		return period("day", now, -1), true
+	case "tomorrow":
+		return period("day", now, 1), true
+	}
+
+	if m := relativePeriod.FindStringSubmatch(s); m != nil {
+		offset := 0
+		switch m[1] {
+		case "last", "previous":
+			offset = -1
+		case "next":
+			offset = 1
+		}
+		return period(m[2], now, offset), true
+	}
+
+	if m := countedPeriod.FindStringSubmatch(s); m != nil {
+		n, err := strconv.Atoi(m[2])
+		if err != nil || n > 10000 {
+			return Range{}, false
+		}
+		shift := func(t time.Time, sign int) time.Time {
+			switch m[3] {
+			case "minute":
+				return t.Add(time.Duration(sign*n) * time.Minute)
+			case "hour":
+				return t.Add(time.Duration(sign*n) * time.Hour)
+			case "day":
+				return t.AddDate(0, 0, sign*n)
+			case "week":
+				return t.AddDate(0, 0, sign*7*n)
+			case "month":
+				return t.AddDate(0, sign*n, 0)
+			}
+			return t.AddDate(sign*n, 0, 0)
+		}
+		if m[1] == "next" {
+			return Range{Start: now, End: shift(now, 1)}, true
+		}
+		return Range{Start: shift(now, -1), End: now}, true
+	}
+
+	if m := quarterPeriod.FindStringSubmatch(s); m != nil {
+		q, _ := strconv.Atoi(m[1])
+		year := now.Year()
+		if m[2] != "" {
+			year, _ = strconv.Atoi(m[2])
+		}
+		return period("quarter", time.Date(year, time.Month(q*3), 1, 0, 0, 0, 0, loc), 0), true
+	}
+
+	if yearPeriod.MatchString(s) {
+		year, _ := strconv.Atoi(s)
+		return period("year", time.Date(year, 1, 1, 0, 0, 0, 0, loc), 0), true
+	}
+
+	if monthPeriod.MatchString(s) {
+		t, err := time.ParseInLocation("2006-01", s, loc)
+		if err != nil {
+			return Range{}, false
+		}
+		return period("month", t, 0), true
+	}
+
+	fields := strings.Fields(s)
+	if month, ok := monthNames[fields[0]]; ok && len(fields) <= 2 {
+		year := now.Year()
+		if len(fields) == 2 {
+			y, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return Range{}, false
+			}
+			year = y
+		}
+		return period("month", time.Date(year, month, 1, 0, 0, 0, 0, loc), 0), true
+	}
+
+	if t, err := p.ParseDate(s, loc); err == nil {
+		return period("day", t, 0), true
+	}
+	return Range{}, false
+}
+
+// rangeEndpoint is one side of an "A to B" range: a period, an instant
+// (day plus time of day) or a bare time of day
+type rangeEndpoint struct {
+	period   Range
+	instant  time.Time
+	hasDay   bool
+	hasClock bool
+	h, m, s  int
+}
+
+// parseEndpoint parses one side of an "A to B" range
+func (p *Parser) parseEndpoint(s string, now time.Time) (rangeEndpoint, bool) {
+	if h, m, sec, ok := parseClockOfDay(s); ok {
+		return rangeEndpoint{hasClock: true, h: h, m: m, s: sec}, true
+	}
+	if r, ok := p.parsePeriod(s, now); ok {
+		return rangeEndpoint{period: r, hasDay: true}, true
+	}
+
+	// "<day> <time>", e.g. "yesterday 9am" or "2024-03-01 17:30"
+	fields := strings.Fields(s)
+	for split := len(fields) - 1; split > 0; split-- {
+		h, m, sec, ok := parseClockOfDay(strings.Join(fields[split:], " "))
+		if !ok {
+			continue
+		}
+		day, ok := p.parsePeriod(strings.Join(fields[:split], " "), now)
+		if !ok {
+			return rangeEndpoint{}, false
+		}
+		d := day.Start
+		instant := time.Date(d.Year(), d.Month(), d.Day(), h, m, sec, 0, d.Location())
+		return rangeEndpoint{period: day, instant: instant, hasDay: true, hasClock: true, h: h, m: m, s: sec}, true
+	}
+	return rangeEndpoint{}, false
+}
+
+// ParseRange parses a natural-language range into its half-open bounds,
+// relative to now (whose location sets the calendar). Supported forms:
+//
+//	today, yesterday, tomorrow, 2024-03-01
+//	this|last|next day|week|month|quarter|year (weeks start on Monday)
+//	last|past|next N minutes|hours|days|weeks|months|years
+//	Q1 2024, 2024, 2024-03, march 2024
+//	<start> to <end>, e.g. "yesterday 9am to 5pm" or "2024-03-01 to 2024-03-05"
+func (p *Parser) ParseRange(input string, now time.Time) (Range, error) {
+	if err := p.checkInput(input); err != nil {
+		return Range{}, err
+	}
+	s := normalizePhrase(input)
+	if s == "" {
+		return Range{}, &ErrInvalidFormat{Input: input}
+	}
+
+	left, right, isSpan := strings.Cut(s, " to ")
+	if !isSpan {
+		if r, ok := p.parsePeriod(s, now); ok {
+			return r, nil
+		}
+		return Range{}, &ErrInvalidFormat{Input: input}
+	}
+
+	from, ok := p.parseEndpoint(strings.TrimSpace(left), now)
+	if !ok || !from.hasDay && !from.hasClock {
+		return Range{}, &ErrInvalidFormat{Input: input}
+	}
+	to, ok := p.parseEndpoint(strings.TrimSpace(right), now)
+	if !ok {
+		return Range{}, &ErrInvalidFormat{Input: input}
+	}
+
+	// A start without a day ("9am to 5pm") means today
+	if !from.hasDay {
+		day := period("day", now, 0)
+		d := day.Start
+		from = rangeEndpoint{
+			period: day, hasDay: true, hasClock: true,
+			instant: time.Date(d.Year(), d.Month(), d.Day(), from.h, from.m, from.s, 0, d.Location()),
+		}
+	}
+
+	var r Range
+	if from.hasClock {
+		r.Start = from.instant
+	} else {
+		r.Start = from.period.Start
+	}
+
+	switch {
+	case to.hasDay && to.hasClock:
+		r.End = to.instant
+	case to.hasDay:
+		r.End = to.period.End
+	default:
+		// A bare end time shares the start's day, or the next one if earlier
+		d := from.period.Start
+		r.End = time.Date(d.Year(), d.Month(), d.Day(), to.h, to.m, to.s, 0, d.Location())
+		if !r.End.After(r.Start) {
+			r.End = r.End.AddDate(0, 0, 1)
+		}
+	}
+
+	if !r.End.After(r.Start) {
+		return Range{}, &ErrInvalidFormat{Input: input}
+	}
+	return r, nil
+}
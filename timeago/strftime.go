@@ -0,0 +1,68 @@
+package timeago
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// strftimeLayouts maps strftime directives to Go layout elements
+var strftimeLayouts = map[string]string{
+	"Y": "2006", "y": "06", "m": "01", "-m": "1", "d": "02", "-d": "2", "e": "_2",
+	"H": "15", "I": "03", "-I": "3", "M": "04", "-M": "4", "S": "05", "-S": "5",
+	"p": "PM", "P": "pm", "z": "-0700", ":z": "-07:00", "Z": "MST",
+	"b": "Jan", "h": "Jan", "B": "January", "a": "Mon", "A": "Monday", "j": "002",
+	"L": "000", "f": "000000", "N": "000000000",
+	"F": "2006-01-02", "T": "15:04:05", "R": "15:04", "D": "01/02/06",
+	"n": "\n", "t": "\t", "%": "%",
+}
+
+// layoutProbes are two instants differing in every layout element, used to
+// detect literal text that Go would read as a layout element
+var layoutProbes = []time.Time{
+	time.Date(1999, 12, 31, 23, 58, 57, 0, time.FixedZone("XST", 3600)),
+	time.Date(2011, 10, 29, 21, 48, 47, 0, time.FixedZone("YST", -7200)),
+}
+
+// StrftimeLayout translates a strftime format ("%Y-%m-%d %H:%M") to a Go
+// layout. Directives without a Go equivalent (%s, %U, %u...) are errors, as
+// is literal text Go would reinterpret ("Jan", digits), since layouts have
+// no escaping.
+func StrftimeLayout(format string) (string, error) {
+	var layout strings.Builder
+	literal := func(s string) error {
+		for _, probe := range layoutProbes {
+			if probe.Format(s) != s {
+				return fmt.Errorf("literal text %q cannot be expressed in a Go layout", s)
+			}
+		}
+		layout.WriteString(s)
+		return nil
+	}
+
+	for format != "" {
+		i := strings.IndexByte(format, '%')
+		if i < 0 {
+			i = len(format)
+		}
+		if err := literal(format[:i]); err != nil {
+			return "", err
+		}
+		format = format[i:]
+		if format == "" {
+			break
+		}
+
+		directive := format[1:min(2, len(format))]
+		if directive == "-" || directive == ":" {
+			directive = format[1:min(3, len(format))]
+		}
+		element, ok := strftimeLayouts[directive]
+		if !ok {
+			return "", fmt.Errorf("unsupported strftime directive %%%s", directive)
+		}
+		layout.WriteString(element)
+		format = format[1+len(directive):]
+	}
+	return layout.String(), nil
+}
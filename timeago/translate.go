@@ -0,0 +1,129 @@
+package timeago
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// localizedTerm matches a number followed by a unit in any script; the
+// space is optional for languages such as Japanese ("2時間")
+var localizedTerm = regexp.MustCompile(`(\d+)\s*(\p{L}+)`)
+
+// inputConnectors join the terms of a duration ("2 Stunden und 5 Minuten")
+var inputConnectors = map[string]bool{
+	"and": true, "und": true, "et": true, "y": true, "e": true, "en": true,
+	"i": true, "и": true, "和": true,
+}
+
+// Translate parses with DefaultParser.Translate
+func Translate(input, lang string) (string, error) {
+	return DefaultParser.Translate(input, lang)
+}
+
+// Translate rewrites a duration or relative phrase written in lang into
+// English, e.g. "hace 2 horas" to "2 hours ago", "3 Tage" to "3 days" or
+// "dans 2 jours" to "in 2 days", for ParseDuration and ParseTime. With an
+// empty lang every non-English locale is tried in LocaleNames order. The
+// whole phrase must be understood, or *ErrInvalidFormat is returned.
+func (p *Parser) Translate(input, lang string) (string, error) {
+	if err := p.checkInput(input); err != nil {
+		return "", err
+	}
+	langs := []string{lang}
+	if lang == "" {
+		langs = LocaleNames()
+	}
+	s := normalizePhrase(input)
+	for _, name := range langs {
+		l, ok := Locales[name]
+		if !ok {
+			return "", fmt.Errorf("unsupported language %q", name)
+		}
+		if name == "en" {
+			continue
+		}
+		if english, ok := l.translate(s); ok {
+			return english, nil
+		}
+	}
+	return "", &ErrInvalidFormat{Input: input}
+}
+
+// affixes splits a "%s" format into its lowercased prefix and suffix
+func affixes(format string) (string, string) {
+	prefix, suffix, _ := strings.Cut(strings.ToLower(format), "%s")
+	return prefix, suffix
+}
+
+// unitWords maps every spelling of the locale's units, including Aliases,
+// to the English unit of the same length
+func (l Locale) unitWords() map[string]Unit {
+	words := map[string]Unit{}
+	for i, unit := range l.Units {
+		english := DefaultUnits[i]
+		words[strings.ToLower(unit.Singular)] = english
+		words[strings.ToLower(unit.Plural)] = english
+		for _, form := range unit.Forms {
+			words[strings.ToLower(form)] = english
+		}
+	}
+	for alias, name := range l.Aliases {
+		for _, unit := range DefaultUnits {
+			if unit.Singular == name {
+				words[alias] = unit
+			}
+		}
+	}
+	return words
+}
+
+// translate rewrites the normalized phrase s, reporting whether every word
+// was understood
+func (l Locale) translate(s string) (string, bool) {
+	if s == normalizePhrase(l.JustNowText) {
+		return "now", true
+	}
+
+	direction := ""
+	for _, dir := range []struct{ format, name string }{{l.PastFormat, "past"}, {l.FutureFormat, "future"}} {
+		prefix, suffix := affixes(dir.format)
+		if len(s) > len(prefix)+len(suffix) && strings.HasPrefix(s, prefix) && strings.HasSuffix(s, suffix) {
+			s = strings.TrimSpace(s[len(prefix) : len(s)-len(suffix)])
+			direction = dir.name
+			break
+		}
+	}
+
+	words := l.unitWords()
+	matches := localizedTerm.FindAllStringSubmatch(s, -1)
+	if len(matches) == 0 {
+		return "", false
+	}
+	terms := make([]string, 0, len(matches))
+	for _, m := range matches {
+		unit, ok := words[m[2]]
+		if !ok {
+			return "", false
+		}
+		name := unit.Plural
+		if m[1] == "1" {
+			name = unit.Singular
+		}
+		terms = append(terms, m[1]+" "+name)
+	}
+	for _, rest := range strings.Fields(localizedTerm.ReplaceAllString(s, " ")) {
+		if !inputConnectors[rest] {
+			return "", false
+		}
+	}
+
+	english := strings.Join(terms, " ")
+	switch direction {
+	case "past":
+		return english + " ago", true
+	case "future":
+		return "in " + english, true
+	}
+	return english, true
+}
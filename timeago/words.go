@@ -0,0 +1,60 @@
+package timeago
+
+import "strings"
+
+var (
+	smallNumbers = []string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen",
+		"seventeen", "eighteen", "nineteen",
+	}
+	tensNumbers = []string{
+		"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety",
+	}
+	scaleNumbers = []struct {
+		value uint64
+		name  string
+	}{
+		{1_000_000_000_000, "trillion"},
+		{1_000_000_000, "billion"},
+		{1_000_000, "million"},
+		{1_000, "thousand"},
+		{100, "hundred"},
+	}
+)
+
+// SpellNumber writes n out in English words, e.g. 42 is "forty-two".
+// It is meant for Formatter.Numbers when output is read aloud.
+func SpellNumber(n int64) string {
+	if n < 0 {
+		// Negated as unsigned, so math.MinInt64 has a magnitude too
+		return "minus " + spellMagnitude(-uint64(n))
+	}
+	return spellMagnitude(uint64(n))
+}
+
+// spellMagnitude writes a non-negative number out in words
+func spellMagnitude(n uint64) string {
+	if n < 20 {
+		return smallNumbers[n]
+	}
+	if n < 100 {
+		word := tensNumbers[n/10]
+		if n%10 != 0 {
+			word += "-" + smallNumbers[n%10]
+		}
+		return word
+	}
+
+	var words []string
+	for _, scale := range scaleNumbers {
+		if n >= scale.value {
+			words = append(words, spellMagnitude(n/scale.value), scale.name)
+			n %= scale.value
+		}
+	}
+	if n > 0 {
+		words = append(words, spellMagnitude(n))
+	}
+	return strings.Join(words, " ")
+}
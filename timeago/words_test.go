@@ -0,0 +1,28 @@
+package timeago
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSpellNumber(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "zero"},
+		{7, "seven"},
+		{42, "forty-two"},
+		{100, "one hundred"},
+		{1_001, "one thousand one"},
+		{-15, "minus fifteen"},
+		{2_500_000, "two million five hundred thousand"},
+		{math.MaxInt64, "nine million two hundred twenty-three thousand three hundred seventy-two trillion thirty-six billion eight hundred fifty-four million seven hundred seventy-five thousand eight hundred seven"},
+		{math.MinInt64, "minus nine million two hundred twenty-three thousand three hundred seventy-two trillion thirty-six billion eight hundred fifty-four million seven hundred seventy-five thousand eight hundred eight"},
+	}
+	for _, tt := range tests {
+		if got := SpellNumber(tt.n); got != tt.want {
+			t.Errorf("SpellNumber(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
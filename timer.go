@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// formatClock renders a remaining duration as MM:SS or HH:MM:SS
+func formatClock(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := int(d / time.Hour)
+	m := int(d % time.Hour / time.Minute)
+	s := int(d % time.Minute / time.Second)
+	if h > 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
+
+// waitUntil blocks until deadline or until ctx is canceled, redrawing a
+// countdown line once per second on a terminal, and at once when it is
+// resized or sent SIGUSR1. The deadline is compared against the wall clock
+// so the wait survives system suspend.
+func waitUntil(ctx context.Context, deadline time.Time, label string, isTTY bool) error {
+	// Drop the monotonic reading: comparisons then use the wall clock
+	deadline = deadline.Round(0)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	resize, reload, stop := displaySignals()
+	defer stop()
+
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			if isTTY {
+				fmt.Print("\r\033[K")
+			}
+			return nil
+		}
+		if isTTY {
+			fmt.Print("\r\033[K" + fitLine(label+" "+formatClock(remaining)))
+		}
+
+		select {
+		case <-ctx.Done():
+			if isTTY {
+				fmt.Print("\r\033[K")
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		case <-resize:
+		case <-reload:
+		}
+	}
+}
+
+// notify rings the terminal bell and raises a desktop notification when a
+// notifier is available; failures are ignored
+func notify(title, message string, isTTY bool) {
+	if isTTY {
+		fmt.Print("\a")
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf("display notification %q with title %q", message, title))
+	default:
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return
+		}
+		cmd = exec.Command("notify-send", title, message)
+	}
+	cmd.Run()
+}
+
+// runHook runs a user command through the shell with extra environment
+// variables, forwarding its output
+func runHook(command string, env ...string) error {
+	if command == "" {
+		return nil
+	}
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
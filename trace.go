@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// traceTime renders an epoch for the trace, e.g. "1700000000000
+// (2023-11-14 22:13:20 UTC)"
+func traceTime(t time.Time) string {
+	return fmt.Sprintf("%d (%s UTC)", t.UnixMilli(), formatDateTime(t, true))
+}
+
+// monthsLabel renders the month step of the trace, e.g. "+1 year -2 months"
+func monthsLabel(years, months int) string {
+	var parts []string
+	if years != 0 {
+		parts = append(parts, fmt.Sprintf("%+d years", years))
+	}
+	if months != 0 {
+		parts = append(parts, fmt.Sprintf("%+d months", months))
+	}
+	return strings.Join(parts, " ")
+}
+
+// traceOffset prints the steps of add or sub on stderr: the base, each
+// term with its fixed length and the running total, then the steps that
+// were actually applied and the result
+func traceOffset(name, value string, base time.Time, calendar timeago.CalendarDuration, fixed bool, jitter time.Duration) error {
+	terms, err := timeago.ParseDurationTerms(value)
+	if err != nil {
+		return err
+	}
+	w := os.Stderr
+	sign := time.Duration(1)
+	if name == "sub" {
+		sign = -1
+	}
+
+	fmt.Fprintf(w, "Base: %s\n", traceTime(base))
+	var total time.Duration
+	for _, term := range terms {
+		total += term.Duration
+		note := ""
+		if term.Calendar && !fixed {
+			note = " (fixed length, the calendar is followed below)"
+		}
+		fmt.Fprintf(w, "Term: %s = %d ms, total %d ms%s\n", term.Text, term.Duration.Milliseconds(), total.Milliseconds(), note)
+	}
+
+	t := base
+	step := func(label string, next time.Time) {
+		if next.Equal(t) {
+			return
+		}
+		fmt.Fprintf(w, "Step: %s -> %s (%+d ms)\n", label, traceTime(next), next.Sub(t).Milliseconds())
+		t = next
+	}
+	switch {
+	case fixed:
+		step(fmt.Sprintf("%+d ms", (sign*total).Milliseconds()), t.Add(sign*total))
+	case name == "add":
+		step(monthsLabel(calendar.Years, calendar.Months),
+			timeago.CalendarDuration{Years: calendar.Years, Months: calendar.Months}.Add(t))
+		step(fmt.Sprintf("%+d days", calendar.Days), t.AddDate(0, 0, calendar.Days))
+		step(fmt.Sprintf("%+d ms", calendar.Clock.Milliseconds()), t.Add(calendar.Clock))
+	default:
+		// Sub undoes Add, smallest units first
+		step(fmt.Sprintf("%+d ms", -calendar.Clock.Milliseconds()), t.Add(-calendar.Clock))
+		step(fmt.Sprintf("%+d days", -calendar.Days), t.AddDate(0, 0, -calendar.Days))
+		step(monthsLabel(-calendar.Years, -calendar.Months),
+			timeago.CalendarDuration{Years: calendar.Years, Months: calendar.Months}.Sub(t))
+	}
+	if jitter != 0 {
+		step("jitter", t.Add(jitter))
+	}
+	fmt.Fprintf(w, "Result: %s, %+d ms from the base\n", traceTime(t), t.Sub(base).Milliseconds())
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	// Zones resolve the same everywhere, even in scratch containers and
+	// minimal images without a zone database
+	_ "time/tzdata"
+)
+
+// zoneinfoWarning makes the missing database warning print once per run
+var zoneinfoWarning sync.Once
+
+// systemZoneinfo reports whether a zone database is installed, including
+// the directory or zip file ZONEINFO points to
+func systemZoneinfo() bool {
+	if path := os.Getenv("ZONEINFO"); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	for _, dir := range zoneinfoDirs {
+		if _, err := os.Stat(filepath.Join(dir, "UTC")); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// warnMissingZoneinfo notes on stderr that zones come from the database
+// embedded at build time, which may be older than the system's would be.
+// Windows never ships one, so the embedded copy is the norm there.
+func warnMissingZoneinfo() {
+	zoneinfoWarning.Do(func() {
+		if runtime.GOOS != "windows" && !systemZoneinfo() {
+			fmt.Fprintf(os.Stderr, "Warning: no time zone database found (install tzdata), using the copy embedded in timeago (%s)\n", runtime.Version())
+		}
+	})
+}
+
+// checkLocalZone warns when TZ names a zone that only the embedded
+// database can resolve
+func checkLocalZone() {
+	if tz := os.Getenv("TZ"); tz != "" && tz != "UTC" && tz[0] != ':' && tz[0] != '/' {
+		if _, err := time.LoadLocation(tz); err == nil {
+			warnMissingZoneinfo()
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ulidDescription details the ulid command for its usage
+const ulidDescription = `Decodes the 48-bit millisecond timestamp that starts a ULID (26 Crockford
+base32 characters, case-insensitive) and shows it like convert. --stdin
+reads one ULID per line and writes one line each, as convert --stdin does;
+--json writes a conversion object per ULID, one per line.`
+
+// crockford maps Crockford base32 digits, and the aliases i, l and o, to
+// their values
+var crockford = func() [256]int8 {
+	var table [256]int8
+	for i := range table {
+		table[i] = -1
+	}
+	for i, c := range "0123456789abcdefghjkmnpqrstvwxyz" {
+		table[c] = int8(i)
+		table[strings.ToUpper(string(c))[0]] = int8(i)
+	}
+	for c, v := range map[byte]int8{'i': 1, 'I': 1, 'l': 1, 'L': 1, 'o': 0, 'O': 0} {
+		table[c] = v
+	}
+	return table
+}()
+
+// ulidTime returns the epoch milliseconds of a ULID
+func ulidTime(s string) (int64, error) {
+	if len(s) != 26 {
+		return 0, fmt.Errorf("invalid ULID %q (expected 26 characters, got %d)", s, len(s))
+	}
+	for i := range len(s) {
+		if crockford[s[i]] < 0 {
+			return 0, fmt.Errorf("invalid ULID %q (%q is not a Crockford base32 digit)", s, s[i])
+		}
+	}
+	// 10 digits hold 50 bits, of which the timestamp uses 48
+	if crockford[s[0]] > 7 {
+		return 0, fmt.Errorf("invalid ULID %q (timestamp overflows 48 bits)", s)
+	}
+	var ms int64
+	for i := range 10 {
+		ms = ms<<5 | int64(crockford[s[i]])
+	}
+	return ms, nil
+}
+
+// runULIDCommand reports the time embedded in ULIDs
+func runULIDCommand(args []string) error {
+	fs := newFlagSet("ulid")
+	out := addOutputFlags(fs, 1)
+	stdin := fs.Bool("stdin", false, "read one ULID per line from stdin")
+	asJSON := fs.Bool("json", false, "write one JSON conversion object per ULID")
+	positional, err := parseArgs("ulid", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if *stdin == (len(positional) > 0) {
+		return errors.New("ulid requires ULIDs as arguments or --stdin")
+	}
+
+	f := newFormatter(out.precision)
+	tty := isTTY()
+	format := batchFormat(out.precision, false, tty)
+	line := func(input string) (string, error) {
+		epochMs, err := ulidTime(input)
+		if err != nil {
+			return "", err
+		}
+		if *asJSON {
+			data, err := json.Marshal(conversion(epochMs, f))
+			return string(data), err
+		}
+		return format(input, epochMs)
+	}
+	if *stdin {
+		return runLines(os.Stdin, os.Stdout, 1024*1024, line)
+	}
+
+	for i, arg := range positional {
+		epochMs, err := ulidTime(arg)
+		if err != nil {
+			return err
+		}
+		switch {
+		case *asJSON:
+			data, err := json.Marshal(conversion(epochMs, f))
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+		case !tty:
+			fmt.Println(emitEpoch(epochMs))
+		default:
+			if i > 0 {
+				fmt.Println()
+			}
+			t := time.UnixMilli(epochMs)
+			fmt.Printf("ULID: %s\n", arg)
+			fmt.Printf("Epoch: %d\n", epochMs)
+			fmt.Printf("UTC: %s\n", formatDateTime(t, true))
+			fmt.Printf("Local: %s\n", formatDateTime(t, false))
+			fmt.Printf("Time ago: %s\n", timeAgo(epochMs, out.precision))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+// untilDescription details the until command for its usage
+const untilDescription = `Blocks until TARGET, a timestamp ("9am tomorrow", ISO 8601, epoch) or a
+duration from now ("90 minutes"), then exits with status 0, so it chains as
+timeago until "9am tomorrow" && ./deploy.sh. The wall clock is re-checked
+every second so the wait survives system suspend; Ctrl-C exits with 130.
+--jitter delays TARGET by a random amount to stagger jobs across machines.`
+
+// resolveTarget reads a timestamp, or a duration counted from now
+func resolveTarget(input string, now time.Time) (time.Time, error) {
+	if epochMs, err := parseEpoch(input); err == nil {
+		return time.UnixMilli(epochMs), nil
+	}
+	if ms, err := parseTimeString(translateInput(input)); err == nil {
+		return now.Add(time.Duration(ms) * time.Millisecond), nil
+	}
+	return time.Time{}, fmt.Errorf("Invalid timestamp or duration %q", input)
+}
+
+// runUntilCommand sleeps until an instant
+func runUntilCommand(args []string) error {
+	fs := newFlagSet("until")
+	var jitter time.Duration
+	fs.Var(durationValue{&jitter}, "jitter", jitterUsage)
+	positional, err := parseArgs("until", fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return errors.New("until requires a timestamp or a duration")
+	}
+	target, err := resolveTarget(strings.Join(positional, " "), clock.Now())
+	if err != nil {
+		return err
+	}
+	target = target.Add(randomJitter(jitter))
+
+	isTTY := isTTY()
+	if isTTY {
+		fmt.Printf("Until: %s (%s)\n", formatDateTime(target, false), timeAgo(target.UnixMilli(), 2))
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := waitUntil(ctx, target, "Remaining", isTTY); err != nil {
+		return err
+	}
+	if !isTTY {
+		fmt.Println(emitEpoch(target.UnixMilli()))
+	}
+	return nil
+}
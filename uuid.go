@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// uuidDescription details the uuid command for its usage
+const uuidDescription = `Extracts the creation time embedded in time-based UUIDs: version 1 and 6
+(100 ns since 1582-10-15) and version 7 (Unix milliseconds). Other versions
+carry no time and are rejected. Accepts the usual spellings: hyphenated,
+plain hex, braces or a urn:uuid: prefix. Piped output is one epoch per UUID.`
+
+// gregorianOffset is the number of 100 ns intervals from the UUID epoch,
+// 1582-10-15, to the Unix epoch
+const gregorianOffset = 0x01B21DD213814000
+
+// parseUUID reads the 16 bytes of a UUID
+func parseUUID(s string) ([16]byte, error) {
+	var u [16]byte
+	text := strings.ToLower(strings.TrimSpace(s))
+	text = strings.TrimPrefix(text, "urn:uuid:")
+	text = strings.TrimSuffix(strings.TrimPrefix(text, "{"), "}")
+	text = strings.ReplaceAll(text, "-", "")
+	if len(text) != 32 {
+		return u, fmt.Errorf("invalid UUID %q", s)
+	}
+	if _, err := hex.Decode(u[:], []byte(text)); err != nil {
+		return u, fmt.Errorf("invalid UUID %q", s)
+	}
+	return u, nil
+}
+
+// uuidTime returns the version of u and the instant embedded in it
+func uuidTime(u [16]byte) (int, time.Time, error) {
+	version := int(u[6] >> 4)
+	if u[8]&0xc0 != 0x80 {
+		return version, time.Time{}, errors.New("not an RFC 9562 UUID (unknown variant), so it has no defined timestamp")
+	}
+	var ticks uint64
+	switch version {
+	case 1:
+		// time_low, time_mid, then time_hi below the version
+		ticks = uint64(binary.BigEndian.Uint16(u[6:8])&0x0fff)<<48 |
+			uint64(binary.BigEndian.Uint16(u[4:6]))<<32 |
+			uint64(binary.BigEndian.Uint32(u[0:4]))
+	case 6:
+		// The same 60 bits as version 1, most significant first
+		ticks = uint64(binary.BigEndian.Uint32(u[0:4]))<<28 |
+			uint64(binary.BigEndian.Uint16(u[4:6]))<<12 |
+			uint64(binary.BigEndian.Uint16(u[6:8])&0x0fff)
+	case 7:
+		ms := int64(binary.BigEndian.Uint64(u[0:8]) >> 16)
+		return version, time.UnixMilli(ms), nil
+	default:
+		return version, time.Time{}, fmt.Errorf("UUID version %d carries no timestamp (only versions 1, 6 and 7 do)", version)
+	}
+	since := int64(ticks) - gregorianOffset
+	return version, time.Unix(since/1e7, since%1e7*100), nil
+}
+
+// runUUIDCommand reports the creation time of time-based UUIDs
+func runUUIDCommand(args []string) error {
+	fs := newFlagSet("uuid")
+	out := addOutputFlags(fs, 2)
+	positional, err := parseArgs("uuid", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return errors.New("uuid requires a UUID")
+	}
+
+	tty := isTTY()
+	for i, arg := range positional {
+		u, err := parseUUID(arg)
+		if err != nil {
+			return err
+		}
+		version, t, err := uuidTime(u)
+		if err != nil {
+			return fmt.Errorf("%s: %s", arg, err)
+		}
+		epochMs := t.UnixMilli()
+		if !tty {
+			fmt.Println(emitEpoch(epochMs))
+			continue
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("UUID: %s (version %d)\n", arg, version)
+		if version == 1 || version == 6 {
+			// Node IDs with the multicast bit set are random, not a MAC
+			kind := "MAC"
+			if u[10]&1 != 0 {
+				kind = "random"
+			}
+			fmt.Printf("Node: %s (%s)\n", net.HardwareAddr(u[10:16]), kind)
+		}
+		fmt.Printf("Epoch: %d\n", epochMs)
+		fmt.Printf("UTC: %s\n", formatDateTime(t, true))
+		fmt.Printf("Local: %s\n", formatDateTime(t, false))
+		fmt.Printf("Time ago: %s\n", timeAgo(epochMs, out.precision))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// watchValue is a flag.Value for --watch: given alone it redraws every
+// second, --watch=5s sets another interval
+type watchValue struct {
+	d *time.Duration
+}
+
+func (v watchValue) String() string {
+	if v.d == nil || *v.d == 0 {
+		return ""
+	}
+	return v.d.String()
+}
+
+func (v watchValue) Set(s string) error {
+	if s == "true" {
+		*v.d = time.Second
+		return nil
+	}
+	if s == "false" {
+		*v.d = 0
+		return nil
+	}
+	return durationValue{v.d}.Set(s)
+}
+
+// IsBoolFlag lets --watch be given without a value
+func (v watchValue) IsBoolFlag() bool { return true }
+
+// displaySignals subscribes to the resize and reload signals of live
+// displays; on systems without them the channels never receive
+func displaySignals() (resize, reload chan os.Signal, stop func()) {
+	resize = make(chan os.Signal, 1)
+	reload = make(chan os.Signal, 1)
+	// Notify without signals would relay every signal
+	if len(resizeSignals) > 0 {
+		signal.Notify(resize, resizeSignals...)
+	}
+	if len(reloadSignals) > 0 {
+		signal.Notify(reload, reloadSignals...)
+	}
+	return resize, reload, func() {
+		signal.Stop(resize)
+		signal.Stop(reload)
+	}
+}
+
+// fitLine truncates a status line to the terminal width, so that redrawing
+// with "\r" never leaves wrapped remnants behind
+func fitLine(line string) string {
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width < 2 {
+		return line
+	}
+	if runes := []rune(line); len(runes) >= width {
+		return string(runes[:width-1])
+	}
+	return line
+}
+
+// watchRelative redraws the relative time of epochMs in place every
+// interval until ctx is canceled. A terminal resize redraws at once and
+// SIGUSR1 re-reads the config file.
+func watchRelative(ctx context.Context, epochMs int64, precision int, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	resize, reload, stop := displaySignals()
+	defer stop()
+
+	for {
+		fmt.Print("\r\033[K" + fitLine("Time ago: "+timeAgo(epochMs, precision)))
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			return ctx.Err()
+		case <-ticker.C:
+		case <-resize:
+		case <-reload:
+			if err := reloadConfig(); err != nil {
+				fmt.Fprintf(os.Stderr, "\r\033[KWarning: %s\n", err)
+			}
+		}
+	}
+}
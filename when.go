@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/studiowebux/timeago/timeago"
+)
+
+// whenDescription details the when command for its usage
+const whenDescription = `Reads a wall-clock time in the IANA zone that ends the phrase ("9am
+America/New_York", "tomorrow 17:30 in Asia/Tokyo", "2024-03-10 09:00
+Europe/London") and shows the same instant in the --in zones (repeatable,
+default the local zone), with the date shift when the day differs. Piped
+output is the epoch.`
+
+// splitZone separates the trailing zone of a "<time> [in] <zone>" phrase
+func splitZone(phrase string) (string, *time.Location, error) {
+	fields := strings.Fields(phrase)
+	if len(fields) < 2 {
+		return "", nil, errors.New(`when requires a time followed by a zone, e.g. "9am America/New_York"`)
+	}
+	loc, err := loadLocation(fields[len(fields)-1])
+	if err != nil {
+		return "", nil, err
+	}
+	rest := fields[:len(fields)-1]
+	if rest[len(rest)-1] == "in" && len(rest) > 1 {
+		rest = rest[:len(rest)-1]
+	}
+	return strings.Join(rest, " "), loc, nil
+}
+
+// dateShift describes how the calendar date of t in loc differs from its
+// date in from: "same day", "next day", "previous day", or "2 days later"
+// for zones more than a day apart
+func dateShift(t time.Time, from, loc *time.Location) string {
+	a, b := t.In(from), t.In(loc)
+	days := timeago.CalendarDays(time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC),
+		time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC))
+	switch {
+	case days == 0:
+		return "same day"
+	case days == 1:
+		return "next day"
+	case days == -1:
+		return "previous day"
+	case days > 0:
+		return fmt.Sprintf("%d days later", days)
+	}
+	return fmt.Sprintf("%d days earlier", -days)
+}
+
+// runWhenCommand converts a wall-clock time elsewhere into other zones
+func runWhenCommand(args []string) error {
+	fs := newFlagSet("when")
+	out := addOutputFlags(fs, 2)
+	var zones zonesValue
+	fs.Var(&zones, "in", "zone to show the time in (repeatable, default: local)")
+	positional, err := parseArgs("when", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+
+	expr, from, err := splitZone(strings.Join(positional, " "))
+	if err != nil {
+		return err
+	}
+	t, err := timeago.ParseTime(expr, clock.Now().In(from))
+	if err != nil {
+		return fmt.Errorf("Invalid time %q in %s (expected e.g. 9am, 17:30 or tomorrow 9am)", expr, from)
+	}
+	if len(zones) == 0 {
+		zones = zonesValue{time.Local}
+	}
+
+	if !isTTY() {
+		fmt.Println(emitEpoch(t.UnixMilli()))
+		return nil
+	}
+	fmt.Printf("%s: %s\n", from, formatInZone(t, from))
+	for _, loc := range zones {
+		name := loc.String()
+		if loc == time.Local {
+			name = "Local"
+		}
+		fmt.Printf("%s: %s, %s\n", name, formatInZone(t, loc), dateShift(t, from, loc))
+	}
+	fmt.Printf("Time ago: %s\n", timeAgo(t.UnixMilli(), out.precision))
+	return nil
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// windowDescription details the assert-window command for its usage
+const windowDescription = `Exits with status 0 inside the window and 2 outside it, e.g. to allow
+deploys during business hours only. Days are names or ranges (mon-fri,
+sat,sun); hours are a half-open range (09-17, 09:30-17:00, 22-06 overnight).
+Piped output is the milliseconds until the window opens (0 inside).`
+
+// weekdayAbbrevs are the day names accepted by --days, Sunday first
+var weekdayAbbrevs = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// parseWeekdayName reads a day name or its three-letter abbreviation
+func parseWeekdayName(s string) (time.Weekday, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for i, name := range weekdayAbbrevs {
+		if len(s) >= 3 && strings.HasPrefix(s, name) {
+			return time.Weekday(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown day %q", s)
+}
+
+// parseDays reads a comma-separated list of days and day ranges; ranges
+// may wrap around the week (fri-mon)
+func parseDays(s string) ([7]bool, error) {
+	var days [7]bool
+	for _, item := range strings.Split(s, ",") {
+		from, to, isRange := strings.Cut(item, "-")
+		first, err := parseWeekdayName(from)
+		if err != nil {
+			return days, err
+		}
+		last := first
+		if isRange {
+			if last, err = parseWeekdayName(to); err != nil {
+				return days, err
+			}
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// parseMinuteOfDay reads "9", "09" or "09:30" as minutes after midnight;
+// "24" is accepted as the end of the day
+func parseMinuteOfDay(s string) (int, error) {
+	hour, minute, hasMinute := strings.Cut(strings.TrimSpace(s), ":")
+	h, err := strconv.Atoi(hour)
+	if err != nil || h < 0 || h > 24 {
+		return 0, fmt.Errorf("invalid hour %q", s)
+	}
+	m := 0
+	if hasMinute {
+		if m, err = strconv.Atoi(minute); err != nil || m < 0 || m > 59 {
+			return 0, fmt.Errorf("invalid hour %q", s)
+		}
+	}
+	if h == 24 && m != 0 {
+		return 0, fmt.Errorf("invalid hour %q", s)
+	}
+	return h*60 + m, nil
+}
+
+// timeWindow is a set of days and a daily [start, end) span in minutes.
+// When end <= start the span crosses midnight and belongs to the day it
+// starts on.
+type timeWindow struct {
+	days       [7]bool
+	start, end int
+}
+
+// contains reports whether t (in the window's zone) is inside the window
+func (w timeWindow) contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	if w.start < w.end {
+		return w.days[t.Weekday()] && minute >= w.start && minute < w.end
+	}
+	// Overnight: the evening part on an allowed day, or the morning part
+	// after an allowed day
+	if minute >= w.start {
+		return w.days[t.Weekday()]
+	}
+	return minute < w.end && w.days[(t.Weekday()+6)%7]
+}
+
+// nextOpen returns when the window next opens after t, searching a week
+// ahead minute by minute
+func (w timeWindow) nextOpen(t time.Time) (time.Time, bool) {
+	next := t.Truncate(time.Minute)
+	for i := 0; i <= 8*24*60; i++ {
+		next = next.Add(time.Minute)
+		if w.contains(next) {
+			return next, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// runAssertWindowCommand exits non-zero when a time is outside the window
+func runAssertWindowCommand(args []string) error {
+	fs := newFlagSet("assert-window")
+	daysArg := fs.String("days", "mon-sun", "allowed days, e.g. mon-fri or sat,sun")
+	hoursArg := fs.String("hours", "00-24", "allowed hours, e.g. 09-17 or 22-06")
+	at := fs.String("at", "", "check this timestamp instead of now")
+	loc := time.Local
+	fs.Var(locationValue{&loc}, "tz", "time zone of the window")
+	positional, err := parseArgs("assert-window", fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return errors.New("assert-window takes no arguments")
+	}
+
+	var w timeWindow
+	if w.days, err = parseDays(*daysArg); err != nil {
+		return fmt.Errorf("--days: %s", err)
+	}
+	from, to, ok := strings.Cut(*hoursArg, "-")
+	if !ok {
+		return errors.New("--hours requires a range such as 09-17")
+	}
+	if w.start, err = parseMinuteOfDay(from); err != nil {
+		return fmt.Errorf("--hours: %s", err)
+	}
+	if w.end, err = parseMinuteOfDay(to); err != nil {
+		return fmt.Errorf("--hours: %s", err)
+	}
+	if w.start == w.end {
+		w.start, w.end = 0, 24*60
+	}
+
+	now := clock.Now()
+	if *at != "" {
+		epochMs, err := parseEpoch(*at)
+		if err != nil {
+			return fmt.Errorf("Invalid timestamp %q", *at)
+		}
+		now = time.UnixMilli(epochMs)
+	}
+	now = now.In(loc)
+
+	inside := w.contains(now)
+	opens, found := w.nextOpen(now)
+	switch {
+	case !isTTY():
+		wait := int64(0)
+		if !inside && found {
+			wait = opens.Sub(now).Milliseconds()
+		}
+		fmt.Println(wait)
+	case inside:
+		fmt.Printf("Inside window (%s %s, %s)\n", *daysArg, *hoursArg, loc)
+	case found:
+		fmt.Printf("Outside window (%s %s, %s): opens %s, %s\n", *daysArg, *hoursArg, loc,
+			opens.Format("Mon 2006-01-02 15:04"), timeAgo(opens.UnixMilli(), 2))
+	default:
+		fmt.Printf("Outside window (%s %s, %s): never opens\n", *daysArg, *hoursArg, loc)
+	}
+
+	if !inside {
+		return exitStatus(2)
+	}
+	return nil
+}
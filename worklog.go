@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// clockTime matches a wall-clock time such as 09:30 or 17:45:10
+var clockTime = regexp.MustCompile(`^\d{1,2}:\d{2}(?::\d{2})?$`)
+
+// parseWorklogTime parses a work-log boundary: epoch ms, ISO 8601 or a
+// wall-clock time (returned as an offset from midnight, with wallClock=true)
+func parseWorklogTime(s string) (t time.Time, wallClock bool, err error) {
+	if clockTime.MatchString(s) {
+		layout := "15:04"
+		if strings.Count(s, ":") == 2 {
+			layout = "15:04:05"
+		}
+		t, err := time.Parse(layout, s)
+		return t, true, err
+	}
+	if epochMs, err := parseEpoch(s); err == nil {
+		return time.UnixMilli(epochMs), false, nil
+	}
+	return time.Time{}, false, fmt.Errorf("invalid time: %s", s)
+}
+
+// worklogEntry totals the time spent on one label
+type worklogEntry struct {
+	label string
+	total time.Duration
+}
+
+// summarizeWorklog reads "start end [label]" lines and totals them per label.
+// Wall-clock ranges ending before they start are taken to cross midnight.
+func summarizeWorklog(r io.Reader) ([]worklogEntry, time.Duration, error) {
+	totals := map[string]time.Duration{}
+	var overall time.Duration
+
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, 0, fmt.Errorf("line %d: expected \"start end [label]\"", lineNo)
+		}
+
+		start, startClock, err := parseWorklogTime(fields[0])
+		if err != nil {
+			return nil, 0, fmt.Errorf("line %d: %s", lineNo, err)
+		}
+		end, endClock, err := parseWorklogTime(fields[1])
+		if err != nil {
+			return nil, 0, fmt.Errorf("line %d: %s", lineNo, err)
+		}
+		if startClock != endClock {
+			return nil, 0, fmt.Errorf("line %d: cannot mix clock times and full timestamps", lineNo)
+		}
+
+		d := end.Sub(start)
+		if d < 0 && startClock {
+			d += 24 * time.Hour
+		}
+		if d < 0 {
+			return nil, 0, fmt.Errorf("line %d: end is before start", lineNo)
+		}
+
+		label := strings.Join(fields[2:], " ")
+		if label == "" {
+			label = "(unlabeled)"
+		}
+		totals[label] += d
+		overall += d
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	entries := make([]worklogEntry, 0, len(totals))
+	for label, total := range totals {
+		entries = append(entries, worklogEntry{label, total})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].total != entries[j].total {
+			return entries[i].total > entries[j].total
+		}
+		return entries[i].label < entries[j].label
+	})
+	return entries, overall, nil
+}
+
+// runWorklogCommand prints the per-label and overall totals of a work log
+// read from stdin. Piped output is tab separated: label and milliseconds.
+func runWorklogCommand(args []string) error {
+	fs := newFlagSet("worklog")
+	out := addOutputFlags(fs, 7)
+	fs.Bool("stdin", true, "read the work log from stdin (the only input)")
+	if _, err := parseArgs("worklog", fs, args); err != nil {
+		return err
+	}
+	if err := out.apply(); err != nil {
+		return err
+	}
+
+	entries, overall, err := summarizeWorklog(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	if !isTTY() {
+		for _, e := range entries {
+			fmt.Printf("%s\t%d\n", e.label, e.total.Milliseconds())
+		}
+		fmt.Printf("total\t%d\n", overall.Milliseconds())
+		return nil
+	}
+
+	f := hoursFormatter(out.precision)
+	width := len("Total")
+	for _, e := range entries {
+		width = max(width, len(e.label))
+	}
+	for _, e := range entries {
+		share := 0.0
+		if overall > 0 {
+			share = float64(e.total) / float64(overall) * 100
+		}
+		fmt.Printf("%-*s  %s (%.1f%%)\n", width, e.label, f.Duration(e.total), share)
+	}
+	fmt.Printf("%-*s  %s\n", width, "Total", f.Duration(overall))
+	return nil
+}
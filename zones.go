@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// zoneinfoDirs are the usual locations of the IANA time zone database
+var zoneinfoDirs = []string{"/usr/share/zoneinfo", "/usr/share/lib/zoneinfo", "/usr/lib/locale/TZ", "/etc/zoneinfo"}
+
+// zoneNames lists the IANA zone names of the installed database, or the
+// bundled names when none is found
+func zoneNames() []string {
+	dirs := zoneinfoDirs
+	if dir := os.Getenv("ZONEINFO"); dir != "" {
+		dirs = append([]string{dir}, dirs...)
+	}
+	for _, dir := range dirs {
+		var names []string
+		filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			name, _ := filepath.Rel(dir, path)
+			if d.IsDir() {
+				// posix/ and right/ duplicate the database with other leap rules
+				if name == "posix" || name == "right" {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if r := []rune(name)[0]; unicode.IsUpper(r) && !strings.Contains(name, ".") {
+				names = append(names, filepath.ToSlash(name))
+			}
+			return nil
+		})
+		if len(names) > 0 {
+			sort.Strings(names)
+			return names
+		}
+	}
+	return bundledZoneNames
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// closeZones suggests up to three zone names resembling name, matching
+// either the full name or its city part ("paris" for Europe/Paris)
+func closeZones(name string) []string {
+	want := strings.ToLower(strings.ReplaceAll(name, " ", "_"))
+	type candidate struct {
+		name     string
+		distance int
+	}
+	var candidates []candidate
+	for _, zone := range zoneNames() {
+		lower := strings.ToLower(zone)
+		distance := editDistance(want, lower)
+		if i := strings.LastIndex(lower, "/"); i >= 0 && !strings.Contains(want, "/") {
+			distance = min(distance, editDistance(want, lower[i+1:]))
+		}
+		if distance <= max(1, len(want)/4) {
+			candidates = append(candidates, candidate{zone, distance})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	var names []string
+	for i := 0; i < len(candidates) && i < 3; i++ {
+		// A case-insensitive match is the only sensible suggestion
+		if candidates[i].distance > candidates[0].distance && candidates[0].distance == 0 {
+			break
+		}
+		names = append(names, candidates[i].name)
+	}
+	return names
+}
+
+// loadLocation loads an IANA time zone, suggesting close matches when the
+// name is unknown
+func loadLocation(name string) (*time.Location, error) {
+	loc, err := time.LoadLocation(name)
+	if err == nil {
+		if name != "UTC" && name != "Local" {
+			warnMissingZoneinfo()
+		}
+		return loc, nil
+	}
+	if names := closeZones(name); len(names) > 0 {
+		return nil, fmt.Errorf("unknown time zone %q, did you mean %s?", name, strings.Join(names, ", "))
+	}
+	return nil, fmt.Errorf("unknown time zone %q (expected an IANA name such as Europe/Paris)", name)
+}
+
+// formatInZone formats t in loc with its zone abbreviation and offset
+func formatInZone(t time.Time, loc *time.Location) string {
+	return roundWallClock(t.In(loc)).Format(dateTimeLayout + " MST (-07:00)")
+}
+
+// defaultWorldZones are shown by the zones and all commands without
+// --zones or --tz, unless the config lists "zones"
+var defaultWorldZones = []string{
+	"UTC", "America/Los_Angeles", "America/New_York", "Europe/London",
+	"Europe/Paris", "Asia/Kolkata", "Asia/Tokyo", "Australia/Sydney",
+}
+
+// dayDifference returns the calendar day difference between t in loc and
+// t in the local zone, e.g. "+1" when it is already tomorrow there
+func dayDifference(t time.Time, loc *time.Location) string {
+	there := t.In(loc)
+	here := t.Local()
+	days := time.Date(there.Year(), there.Month(), there.Day(), 0, 0, 0, 0, time.UTC).
+		Sub(time.Date(here.Year(), here.Month(), here.Day(), 0, 0, 0, 0, time.UTC)) / (24 * time.Hour)
+	if days == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%+d", days)
+}
+
+// runZonesCommand renders one instant in many time zones at once
+func runZonesCommand(args []string) error {
+	fs := newFlagSet("zones")
+	list := fs.String("zones", "", "comma-separated IANA zones (default: the config zones or a set of major cities)")
+	var now string
+	fs.Var(nowValue{&now}, "now", nowUsage)
+	positional, err := parseArgs("zones", fs, args)
+	if err != nil {
+		return err
+	}
+	if err := setNow(now); err != nil {
+		return err
+	}
+
+	t := clock.Now()
+	if len(positional) > 0 {
+		epochMs, err := parseEpoch(strings.Join(positional, " "))
+		if err != nil {
+			return fmt.Errorf("Invalid timestamp %q", strings.Join(positional, " "))
+		}
+		t = time.UnixMilli(epochMs)
+	}
+
+	names, err := loadZones()
+	if err != nil {
+		return err
+	}
+	if *list != "" {
+		names = strings.Split(*list, ",")
+	}
+	zones := []*time.Location{time.Local}
+	for _, name := range names {
+		loc, err := loadLocation(strings.TrimSpace(name))
+		if err != nil {
+			return err
+		}
+		zones = append(zones, loc)
+	}
+
+	tty := isTTY()
+	if tty {
+		fmt.Printf("%-22s %-10s %-8s %-7s %s\n", "ZONE", "DATE", "TIME", "OFFSET", "DAY")
+	}
+	for _, loc := range zones {
+		local := t.In(loc)
+		name := loc.String()
+		if loc == time.Local {
+			name = "Local"
+		}
+		row := []string{name, local.Format("2006-01-02"), local.Format("15:04:05"), local.Format("-07:00"), dayDifference(t, loc)}
+		if tty {
+			fmt.Println(strings.TrimRight(fmt.Sprintf("%-22s %-10s %-8s %-7s %s", row[0], row[1], row[2], row[3], row[4]), " "))
+		} else {
+			fmt.Println(strings.Join(row, "\t"))
+		}
+	}
+	return nil
+}